@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Give bookmarks more than one name",
+	Long: `Give a bookmark synonyms: extra names that jump to it, match fuzzily and
+complete like its alias, and count their uses towards it. 'fn list' still
+shows the bookmark once, under its alias, with its synonyms alongside.`,
+	Example: `  fn alias add k8s kube
+  fn alias add k8s cluster
+  fn alias rm cluster`,
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:               "add <bookmark> <synonym>",
+	Short:             "Add a synonym for a bookmark",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, synonym := args[0], args[1]
+		if !isValidAlias(synonym) {
+			return fmt.Errorf("invalid synonym: use only alphanumeric characters, dash, and underscore")
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		alias, _, exists := store.Lookup(name)
+		if !exists {
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, name)
+		}
+		if err := store.AddSynonym(alias, synonym); err != nil {
+			return fmt.Errorf("failed to add synonym: %w", err)
+		}
+
+		color.Green("✓ '%s' now also answers to '%s'", alias, synonym)
+		return nil
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <synonym>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a synonym, keeping its bookmark",
+	Args:    cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		store, err := storage.NewStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var completions []string
+		for synonym, alias := range store.GetSynonyms() {
+			completions = append(completions, synonym+"\tsynonym of "+alias)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		alias, isSynonym := store.GetSynonyms()[args[0]]
+		if !isSynonym {
+			return fmt.Errorf("'%s' is not a synonym", args[0])
+		}
+		if err := store.RemoveSynonym(args[0]); err != nil {
+			return fmt.Errorf("failed to remove synonym: %w", err)
+		}
+
+		color.Green("✓ Removed synonym '%s' of '%s'", args[0], alias)
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:               "list [bookmark]",
+	Aliases:           []string{"ls"},
+	Short:             "List synonyms",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		aliases := sortedAliases(store.GetAllBookmarks())
+		if len(args) == 1 {
+			alias, _, exists := store.Lookup(args[0])
+			if !exists {
+				return fmt.Errorf("%w: %s", storage.ErrNotFound, args[0])
+			}
+			aliases = []string{alias}
+		}
+
+		shown := 0
+		for _, alias := range aliases {
+			synonyms := store.SynonymsOf(alias)
+			if len(synonyms) == 0 {
+				continue
+			}
+			shown++
+			for _, synonym := range synonyms {
+				fmt.Printf("%s → %s\n", color.YellowString("%-12s", synonym), alias)
+			}
+		}
+		if shown == 0 {
+			fmt.Println("No synonyms set")
+		}
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasAddCmd, aliasRemoveCmd, aliasListCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestAliasCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("k8s", tempDir)
+
+	if err := aliasAddCmd.RunE(aliasAddCmd, []string{"k8s", "bad synonym"}); err == nil {
+		t.Error("Expected an error for an invalid synonym")
+	}
+	if err := aliasAddCmd.RunE(aliasAddCmd, []string{"k8s", "kube"}); err != nil {
+		t.Fatalf("alias add failed: %v", err)
+	}
+
+	store, _ = storage.NewStore()
+	if err := saveCmd.RunE(saveCmd, []string{"kube", tempDir}); err == nil {
+		t.Error("Expected save to refuse a name used as a synonym")
+	}
+	if got := resolutionFor(store, "k8s", "kube"); got != storage.ResolutionExact {
+		t.Errorf("Expected a synonym to resolve exactly, got %v", got)
+	}
+	if got := resolutionFor(store, "k8s", "ku"); got != storage.ResolutionFuzzy {
+		t.Errorf("Expected a partial synonym to resolve fuzzily, got %v", got)
+	}
+
+	completions, _ := nameCompletionFunc(navigateCmd, nil, "")
+	want := map[string]bool{"k8s": false, "kube\tsynonym of k8s": false}
+	for _, completion := range completions {
+		if _, ok := want[completion]; ok {
+			want[completion] = true
+		}
+	}
+	for completion, seen := range want {
+		if !seen {
+			t.Errorf("Expected completion %q, got %v", completion, completions)
+		}
+	}
+
+	if err := aliasRemoveCmd.RunE(aliasRemoveCmd, []string{"kube"}); err != nil {
+		t.Fatalf("alias rm failed: %v", err)
+	}
+	store, _ = storage.NewStore()
+	if _, _, exists := store.Lookup("kube"); exists {
+		t.Error("Expected the synonym to be gone")
+	}
+	if _, exists := store.GetBookmark("k8s"); !exists {
+		t.Error("Expected the bookmark to remain")
+	}
+}
@@ -1,18 +1,27 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/rethil/fast-nav/internal/remote"
 	"github.com/rethil/fast-nav/internal/storage"
 )
 
+var cleanupProbe bool
+
 var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Remove bookmarks pointing to non-existent directories",
-	Long:  `Remove all bookmarks that point to directories that no longer exist.`,
+	Long: `Remove all bookmarks that point to directories that no longer exist.
+
+Remote (ssh://) bookmarks are kept unless --probe is given, which asks each
+host over ssh and removes those whose directory is gone. Hosts that cannot
+be reached are left alone.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		store, err := storage.NewStore()
 		if err != nil {
@@ -21,9 +30,28 @@ var cleanupCmd = &cobra.Command{
 		
 		bookmarks := store.GetAllBookmarks()
 		var removed []string
+		var records []bookmarkRecord
 		
+		missing := func(alias string, bookmark *storage.Bookmark) bool {
+			if !storage.IsRemote(bookmark.Path) {
+				return storage.Missing(bookmark.Path)
+			}
+			if !cleanupProbe {
+				return false
+			}
+			target, err := remote.Parse(bookmark.Path)
+			if err == nil {
+				err = target.Probe()
+			}
+			if err != nil && !errors.Is(err, storage.ErrPathMissing) {
+				color.New(color.FgYellow).Fprintf(os.Stderr, "⚠️  Keeping '%s': %v\n", alias, err)
+			}
+			return errors.Is(err, storage.ErrPathMissing)
+		}
+
 		for alias, bookmark := range bookmarks {
-			if _, err := os.Stat(bookmark.Path); os.IsNotExist(err) {
+			if missing(alias, bookmark) {
+				records = append(records, newBookmarkRecord(alias, bookmark))
 				err := store.DeleteBookmark(alias)
 				if err != nil {
 					return fmt.Errorf("failed to delete bookmark '%s': %w", alias, err)
@@ -31,6 +59,13 @@ var cleanupCmd = &cobra.Command{
 				removed = append(removed, alias)
 			}
 		}
+		sort.Strings(removed)
+
+		if machineOutput() {
+			// Report the removed bookmarks
+			sort.Slice(records, func(i, j int) bool { return records[i].Alias < records[j].Alias })
+			return writeRecords(os.Stdout, records)
+		}
 		
 		if len(removed) == 0 {
 			color.Green("✓ All bookmarks are valid - no cleanup needed")
@@ -43,4 +78,8 @@ var cleanupCmd = &cobra.Command{
 		
 		return nil
 	},
-}
\ No newline at end of file
+}
+
+func init() {
+	cleanupCmd.Flags().BoolVar(&cleanupProbe, "probe", false, "also check remote bookmarks over ssh and remove missing ones")
+}
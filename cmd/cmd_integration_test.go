@@ -6,9 +6,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/mitchellh/go-homedir"
 	"github.com/rethil/fast-nav/internal/storage"
 )
 
+func init() {
+	// Tests point HOME at temporary directories, so home lookups must not be cached
+	homedir.DisableCache = true
+}
+
 // Integration tests for CLI commands
 // These tests focus on core functionality without mocking cobra's execution
 
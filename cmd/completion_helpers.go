@@ -20,4 +20,31 @@ func aliasCompletionFunc(cmd *cobra.Command, args []string, toComplete string) (
 	}
 	
 	return aliases, cobra.ShellCompDirectiveNoFileComp
-}
\ No newline at end of file
+}
+
+// leadingAliasCompletion completes an alias for the first argument only, for
+// commands whose other arguments are new names, values or shell code
+func leadingAliasCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return aliasCompletionFunc(cmd, args, toComplete)
+}
+
+// nameCompletionFunc completes aliases and their synonyms, for commands that
+// accept either
+func nameCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	aliases, directive := aliasCompletionFunc(cmd, args, toComplete)
+	if directive == cobra.ShellCompDirectiveError {
+		return nil, directive
+	}
+
+	store, err := storage.NewStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	for synonym, alias := range store.GetSynonyms() {
+		aliases = append(aliases, synonym+"\tsynonym of "+alias)
+	}
+	return aliases, directive
+}
@@ -23,7 +23,7 @@ var deleteCmd = &cobra.Command{
 		
 		_, exists := store.GetBookmark(alias)
 		if !exists {
-			return fmt.Errorf("alias '%s' not found", alias)
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, alias)
 		}
 		
 		// Confirmation prompt
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -263,6 +264,46 @@ func TestE2EWorkflow(t *testing.T) {
 		}
 	})
 
+	// Test 9b: Errors map to documented exit codes
+	t.Run("ExitCodes", func(t *testing.T) {
+		exitCodeOf := func(err error) int {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return exitErr.ExitCode()
+			}
+			return 0
+		}
+
+		_, err := runFn("path", "nonexistent-bookmark")
+		if code := exitCodeOf(err); code != ExitNotFound {
+			t.Errorf("Expected exit code %d for missing alias, got %d", ExitNotFound, code)
+		}
+
+		goneDir := filepath.Join(tempDir, "gone")
+		os.MkdirAll(goneDir, 0755)
+		saveCmd := exec.Command(binaryPath, "save", "gone")
+		saveCmd.Dir = goneDir
+		saveCmd.Env = append(os.Environ(), "HOME="+tempDir)
+		if err := saveCmd.Run(); err != nil {
+			t.Fatalf("Save command failed: %v", err)
+		}
+		os.RemoveAll(goneDir)
+
+		_, err = runFn("navigate", "gone")
+		if code := exitCodeOf(err); code != ExitPathMissing {
+			t.Errorf("Expected exit code %d for missing directory, got %d", ExitPathMissing, code)
+		}
+
+		corruptHome := filepath.Join(tempDir, "corrupt-home")
+		os.MkdirAll(filepath.Join(corruptHome, ".fn"), 0755)
+		os.WriteFile(filepath.Join(corruptHome, ".fn", "bookmarks.json"), []byte("{not json"), 0644)
+		listCmd := exec.Command(binaryPath, "list")
+		listCmd.Env = append(os.Environ(), "HOME="+corruptHome)
+		if code := exitCodeOf(listCmd.Run()); code != ExitCorruptStore {
+			t.Errorf("Expected exit code %d for corrupt store, got %d", ExitCorruptStore, code)
+		}
+	})
+
 	// Test 10: Help command
 	t.Run("HelpCommand", func(t *testing.T) {
 		output, err := runFn("--help")
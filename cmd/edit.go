@@ -25,7 +25,7 @@ var editCmd = &cobra.Command{
 		// Check if alias exists
 		_, exists := store.GetBookmark(alias)
 		if !exists {
-			return fmt.Errorf("alias '%s' does not exist", alias)
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, alias)
 		}
 		
 		// Get current directory
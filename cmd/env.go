@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envShell     string
+	envIfChanged string
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env --shell <bash|zsh|fish>",
+	Short: "Print bookmarks as shell named directories or variables",
+	Long: `Print shell code that makes every bookmark usable outside fn:
+
+  zsh   hash -d entries, so ~proj expands anywhere
+  bash  exported variables plus 'shopt -s cdable_vars', so 'cd proj' works
+        and $proj expands anywhere
+  fish  abbreviations, so ~proj expands anywhere on the command line
+        (fish 3.6 or later)
+
+Aliases the shell cannot use as a name are skipped with a comment. In bash,
+aliases written in capitals, or that would replace a variable already set in
+the shell or its environment, are skipped too.
+
+Running the output again removes entries for deleted bookmarks. To keep them
+in sync automatically, use 'fn init <shell> --env', which refreshes them
+before each prompt whenever the bookmarks have changed.
+
+To export variables such as AWS_PROFILE only while you are inside a
+bookmark, use 'fn env set'.`,
+	Example: `  eval "$(fn env --shell zsh)"
+  fn env --shell fish | source`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		bookmarks := store.GetAllBookmarks()
+		stamp := envStamp(bookmarks)
+		if envIfChanged != "" && envIfChanged == stamp {
+			return nil
+		}
+
+		script, err := envScript(envShell, bookmarks, stamp)
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// envStamp fingerprints the alias/path pairs, so the prompt hook only
+// reloads when a bookmark was added, removed or re-pointed
+func envStamp(bookmarks map[string]*storage.Bookmark) string {
+	h := fnv.New64a()
+	for _, alias := range sortedAliases(bookmarks) {
+		fmt.Fprintf(h, "%s\x00%s\x00", alias, bookmarks[alias].Path)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func sortedAliases(bookmarks map[string]*storage.Bookmark) []string {
+	aliases := make([]string, 0, len(bookmarks))
+	for alias := range bookmarks {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+var (
+	shellVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	upperCaseName     = regexp.MustCompile(`^[A-Z0-9_]+$`)
+	numericName       = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// envScript renders the named directories, variables or abbreviations for
+// one shell. Names set by a previous run are removed first.
+func envScript(shell string, bookmarks map[string]*storage.Bookmark, stamp string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fn bookmarks (generated by '%s env --shell %s')\n", binaryName(), shell)
+
+	var names []string
+	skip := func(alias, reason string) {
+		fmt.Fprintf(&b, "# skipped %s: %s\n", alias, reason)
+	}
+
+	local := make(map[string]*storage.Bookmark, len(bookmarks))
+	for _, alias := range sortedAliases(bookmarks) {
+		if storage.IsRemote(bookmarks[alias].Path) {
+			skip(alias, "remote target")
+			continue
+		}
+		local[alias] = bookmarks[alias]
+	}
+	bookmarks = local
+
+	switch shell {
+	case "bash":
+		owned := make(map[string]bool)
+		for _, name := range strings.Fields(os.Getenv("__FN_ENV_VARS")) {
+			owned[name] = true
+		}
+		// Plain shell variables are invisible here, so each export is
+		// guarded and __FN_ENV_VARS lists only the names actually set
+		b.WriteString("for __fn_name in ${__FN_ENV_VARS-}; do unset -v \"$__fn_name\"; done\n")
+		b.WriteString("__FN_ENV_VARS=\n")
+		b.WriteString("shopt -s cdable_vars\n")
+		for _, alias := range sortedAliases(bookmarks) {
+			_, inEnv := os.LookupEnv(alias)
+			switch {
+			case !shellVariableName.MatchString(alias):
+				skip(alias, "not a valid variable name")
+			case upperCaseName.MatchString(alias):
+				skip(alias, "capitalised names are left to the environment")
+			case inEnv && !owned[alias]:
+				skip(alias, "already set in the environment")
+			default:
+				fmt.Fprintf(&b, "if [ -z \"${%[1]s+x}\" ]; then export %[1]s=%[2]s; __FN_ENV_VARS=\"$__FN_ENV_VARS %[1]s\"; fi\n",
+					alias, posixQuote(bookmarks[alias].Path))
+			}
+		}
+		b.WriteString("export __FN_ENV_VARS\n")
+		fmt.Fprintf(&b, "__fn_env_stamp=%s\n", stamp)
+	case "zsh":
+		b.WriteString("for __fn_name in ${=__fn_env_dirs-}; do unhash -d -- \"$__fn_name\" 2>/dev/null; done\n")
+		for _, alias := range sortedAliases(bookmarks) {
+			// ~1, ~2, ... refer to the directory stack
+			if numericName.MatchString(alias) {
+				skip(alias, "numeric names refer to the directory stack")
+				continue
+			}
+			fmt.Fprintf(&b, "hash -d -- %s=%s\n", alias, posixQuote(bookmarks[alias].Path))
+			names = append(names, alias)
+		}
+		fmt.Fprintf(&b, "typeset -g __fn_env_dirs=%s\n", posixQuote(strings.Join(names, " ")))
+		fmt.Fprintf(&b, "typeset -g __fn_env_stamp=%s\n", stamp)
+	case "fish":
+		b.WriteString("for __fn_name in $__fn_env_abbrs; abbr --erase -- $__fn_name; end\n")
+		for _, alias := range sortedAliases(bookmarks) {
+			name := "~" + alias
+			fmt.Fprintf(&b, "abbr --add --position anywhere -- %s %s\n", fishQuote(name), fishQuote(bookmarks[alias].Path))
+			names = append(names, fishQuote(name))
+		}
+		fmt.Fprintf(&b, "set -g __fn_env_abbrs %s\n", strings.Join(names, " "))
+		fmt.Fprintf(&b, "set -g __fn_env_stamp %s\n", stamp)
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (want bash, zsh or fish)", shell)
+	}
+
+	return b.String(), nil
+}
+
+// envHook returns shell code that re-runs 'fn env' before each prompt. The
+// --if-changed stamp keeps it from printing anything unless bookmarks changed.
+func envHook(shell, binary string) (string, error) {
+	switch shell {
+	case "bash":
+		return fmt.Sprintf(`__fn_env() {
+    eval "$(command %[1]s env --shell bash --if-changed "${__fn_env_stamp:-none}" 2>/dev/null)"
+}
+case ";${PROMPT_COMMAND:-};" in
+    *";__fn_env;"*) ;;
+    *) PROMPT_COMMAND="__fn_env${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+__fn_env
+`, binary), nil
+	case "zsh":
+		return fmt.Sprintf(`__fn_env() {
+    eval "$(command %[1]s env --shell zsh --if-changed "${__fn_env_stamp:-none}" 2>/dev/null)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook precmd __fn_env
+__fn_env
+`, binary), nil
+	case "fish":
+		return fmt.Sprintf(`function __fn_env --on-event fish_prompt
+    set -q __fn_env_stamp; or set -g __fn_env_stamp none
+    command %[1]s env --shell fish --if-changed $__fn_env_stamp 2>/dev/null | source
+end
+__fn_env
+`, binary), nil
+	}
+	return "", fmt.Errorf("fn env does not support %s", shell)
+}
+
+// posixQuote single-quotes s for sh, bash and zsh
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish, where only \ and ' are special
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envShell, "shell", "", "shell to print code for: bash, zsh or fish")
+	envCmd.Flags().StringVar(&envIfChanged, "if-changed", "", "print nothing if the bookmarks still match this stamp")
+	envCmd.Flags().MarkHidden("if-changed")
+	envCmd.MarkFlagRequired("shell")
+
+	envCmd.RegisterFlagCompletionFunc("shell", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"bash", "zsh", "fish"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
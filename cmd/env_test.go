@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestEnvScriptBash(t *testing.T) {
+	os.Setenv("fn_env_taken", "1")
+	defer os.Unsetenv("fn_env_taken")
+
+	bookmarks := map[string]*storage.Bookmark{
+		"proj":         {Path: `/src/it's "quoted" $HOME`},
+		"my-app":       {Path: "/src/app"},
+		"PATH":         {Path: "/src/path"},
+		"fn_env_taken": {Path: "/src/taken"},
+	}
+	script, err := envScript("bash", bookmarks, envStamp(bookmarks))
+	if err != nil {
+		t.Fatalf("envScript failed: %v", err)
+	}
+
+	for _, skipped := range []string{"my-app", "PATH", "fn_env_taken"} {
+		if !strings.Contains(script, "# skipped "+skipped+":") {
+			t.Errorf("Expected %s to be skipped:\n%s", skipped, script)
+		}
+	}
+
+	// A stale variable from an earlier run should be removed, one the user
+	// set in the shell kept, and the rest exported
+	bookmarks["mine"] = &storage.Bookmark{Path: "/src/mine"}
+	script, _ = envScript("bash", bookmarks, envStamp(bookmarks))
+	shell := exec.Command("bash", "-c", "mine=kept\n"+script+
+		`printf '%s\n' "$mine" "${old-unset}" "$__FN_ENV_VARS"; printenv proj; shopt -q cdable_vars && echo cdable`)
+	shell.Env = append(os.Environ(), "old=/src/old", "__FN_ENV_VARS=old")
+	out, err := shell.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash failed: %v\n%s", err, out)
+	}
+	want := "kept\nunset\n proj\n" + `/src/it's "quoted" $HOME` + "\ncdable\n"
+	if string(out) != want {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+}
+
+func TestEnvScriptQuoting(t *testing.T) {
+	bookmarks := map[string]*storage.Bookmark{
+		"proj": {Path: `/src/it's \ here`},
+		"1":    {Path: "/src/one"},
+	}
+
+	zsh, err := envScript("zsh", bookmarks, "stamp")
+	if err != nil {
+		t.Fatalf("envScript(zsh) failed: %v", err)
+	}
+	if !strings.Contains(zsh, `hash -d -- proj='/src/it'\''s \ here'`) {
+		t.Errorf("Unexpected zsh output:\n%s", zsh)
+	}
+	if !strings.Contains(zsh, "# skipped 1:") {
+		t.Errorf("Expected numeric alias to be skipped in zsh:\n%s", zsh)
+	}
+
+	fish, err := envScript("fish", bookmarks, "stamp")
+	if err != nil {
+		t.Fatalf("envScript(fish) failed: %v", err)
+	}
+	if !strings.Contains(fish, `abbr --add --position anywhere -- '~proj' '/src/it\'s \\ here'`) {
+		t.Errorf("Unexpected fish output:\n%s", fish)
+	}
+
+	if _, err := envScript("tcsh", bookmarks, "stamp"); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestEnvStamp(t *testing.T) {
+	bookmarks := map[string]*storage.Bookmark{"proj": {Path: "/src/proj", UsedCount: 1}}
+	stamp := envStamp(bookmarks)
+
+	bookmarks["proj"].UsedCount = 5
+	if envStamp(bookmarks) != stamp {
+		t.Error("Usage changes should not change the stamp")
+	}
+
+	bookmarks["proj"].Path = "/src/other"
+	if envStamp(bookmarks) == stamp {
+		t.Error("Re-pointing a bookmark should change the stamp")
+	}
+}
+
+func TestEnvHookSyntax(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		hook, err := envHook(shell, "fn")
+		if err != nil {
+			t.Fatalf("envHook(%s) failed: %v", shell, err)
+		}
+		check := exec.Command("bash", "-n")
+		check.Stdin = strings.NewReader(hook)
+		if out, err := check.CombinedOutput(); err != nil {
+			t.Errorf("%s env hook has syntax errors: %v\n%s", shell, err, out)
+		}
+	}
+	if _, err := envHook("tcsh", "fn"); err == nil {
+		t.Error("Expected error for tcsh")
+	}
+}
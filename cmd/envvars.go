@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enterShell  string
+	enterActive string
+)
+
+var envSetCmd = &cobra.Command{
+	Use:   "set <alias> KEY=VALUE...",
+	Short: "Set variables exported while inside a bookmark",
+	Long: `Set environment variables that the shell exports whenever you are in the
+bookmark's directory or below it, and restores to their previous values when
+you leave. In a nested bookmark with variables of its own, only the innermost
+bookmark's variables apply.
+
+Like hooks, variables only take effect once allowed on this machine; see
+'fn hook'. Changes take effect the next time you change directory. Needs the
+bash, zsh or fish wrapper from 'fn init'.`,
+	Example: `  fn env set infra AWS_PROFILE=prod-ro
+  fn env unset infra AWS_PROFILE`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias := args[0]
+		vars := make(map[string]string)
+		for _, arg := range args[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok || !shellVariableName.MatchString(key) {
+				return fmt.Errorf("invalid variable: %s (want KEY=VALUE)", arg)
+			}
+			vars[key] = value
+		}
+
+		return updateEnv(alias, func(env map[string]string) {
+			for key, value := range vars {
+				env[key] = value
+			}
+		})
+	},
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:               "unset <alias> KEY...",
+	Short:             "Remove variables from a bookmark",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateEnv(args[0], func(env map[string]string) {
+			for _, key := range args[1:] {
+				delete(env, key)
+			}
+		})
+	},
+}
+
+// updateEnv edits a copy of a bookmark's variables and stores the result
+func updateEnv(alias string, edit func(map[string]string)) error {
+	var env map[string]string
+	allowed, err := updateOnEnter(alias, func(store *storage.Store) error {
+		bookmark, _ := store.GetBookmark(alias)
+		env = make(map[string]string, len(bookmark.Env))
+		for key, value := range bookmark.Env {
+			env[key] = value
+		}
+		edit(env)
+		return store.SetEnv(alias, env)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(env) == 0 {
+		color.Green("✓ '%s' sets no variables", alias)
+	} else {
+		color.Green("✓ '%s' sets %s", alias, strings.Join(sortedKeys(env), ", "))
+	}
+	warnNotAllowed(alias, allowed)
+	return nil
+}
+
+// enterCmd is run by the shell integration whenever the working directory
+// changes. It prints code that restores the variables of the bookmark being
+// left and exports those of the bookmark being entered.
+var enterCmd = &cobra.Command{
+	Use:    "_enter <dir>",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		alias, bookmark := envBookmarkFor(store.GetAllBookmarks(), args[0])
+		var env map[string]string
+		stamp := ""
+		if bookmark != nil {
+			trust, err := storage.LoadTrust()
+			if err != nil {
+				return err
+			}
+			trusted := trust.Trusted(alias, bookmark)
+			stamp = enterStamp(alias, bookmark, trusted)
+			if trusted {
+				env = bookmark.Env
+			}
+		}
+		if stamp == enterActive {
+			return nil
+		}
+		if bookmark != nil && env == nil {
+			fmt.Fprintf(os.Stderr, "warning: variables of '%s' are not allowed; review them with 'fn hook show %s', then run 'fn hook allow %s'\n", alias, alias, alias)
+		}
+
+		script, err := enterScript(enterShell, env, stamp)
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// envBookmarkFor returns the innermost bookmark with variables whose
+// directory contains dir, preferring the first alias when several share it
+func envBookmarkFor(bookmarks map[string]*storage.Bookmark, dir string) (string, *storage.Bookmark) {
+	dir = filepath.Clean(dir)
+	var found string
+	for _, alias := range sortedAliases(bookmarks) {
+		bookmark := bookmarks[alias]
+		if len(bookmark.Env) == 0 || storage.IsRemote(bookmark.Path) {
+			continue
+		}
+		root := filepath.Clean(bookmark.Dir())
+		if !isWithin(dir, root) {
+			continue
+		}
+		if found == "" || len(root) > len(filepath.Clean(bookmarks[found].Dir())) {
+			found = alias
+		}
+	}
+	if found == "" {
+		return "", nil
+	}
+	return found, bookmarks[found]
+}
+
+// isWithin reports whether path is dir or below it; both must be clean
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// enterStamp identifies what the shell has applied, so that moving around
+// inside the same bookmark prints nothing, while editing or allowing its
+// variables applies them again
+func enterStamp(alias string, bookmark *storage.Bookmark, trusted bool) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%t\x00", alias, bookmark.Dir(), trusted)
+	for _, key := range sortedKeys(bookmark.Env) {
+		fmt.Fprintf(h, "%s=%s\x00", key, bookmark.Env[key])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// enterScript renders the switch to a new set of variables: the previous
+// values saved on the last switch are restored, then the current value of
+// each new variable is saved before it is exported. The bash script is also
+// what sh, dash and ash get, so it quotes saved values with sed rather than
+// printf %q; the trailing x keeps trailing newlines from being stripped.
+func enterScript(shell string, env map[string]string, stamp string) (string, error) {
+	var b strings.Builder
+	switch shell {
+	case "bash", "zsh":
+		unset := "unset -v"
+		if shell == "zsh" {
+			unset = "unset"
+		}
+		b.WriteString("eval \"${__fn_env_restore-}\"\n")
+		b.WriteString("__fn_env_restore=\n")
+		fmt.Fprintf(&b, "__fn_env_active=%s\n", stamp)
+		for _, key := range sortedKeys(env) {
+			fmt.Fprintf(&b, `if [ -n "${%[1]s+x}" ]; then __fn_env_saved=$(printf '%%sx' "$%[1]s" | sed "s/'/'\\\\''/g"); __fn_env_restore="${__fn_env_restore}export %[1]s='${__fn_env_saved%%x}';"; else __fn_env_restore="${__fn_env_restore}%[2]s %[1]s;"; fi`+"\n", key, unset)
+			fmt.Fprintf(&b, "export %s=%s\n", key, posixQuote(env[key]))
+		}
+		fmt.Fprintf(&b, "%s __fn_env_saved\n", unset)
+	case "fish":
+		b.WriteString("eval \"$__fn_env_restore\"\n")
+		b.WriteString("set -g __fn_env_restore ''\n")
+		fmt.Fprintf(&b, "set -g __fn_env_active '%s'\n", stamp)
+		for _, key := range sortedKeys(env) {
+			fmt.Fprintf(&b, `if set -q %[1]s; set -g __fn_env_restore "$__fn_env_restore"(string join ' ' -- set -gx %[1]s (string escape -- $%[1]s))'; '; else; set -g __fn_env_restore "$__fn_env_restore"'set -e %[1]s; '; end`+"\n", key)
+			fmt.Fprintf(&b, "set -gx %s %s\n", key, fishQuote(env[key]))
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (want bash, zsh or fish)", shell)
+	}
+	return b.String(), nil
+}
+
+// enterHook returns shell code that runs 'fn _enter' whenever the working
+// directory changes
+func enterHook(shell, binary string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		install := `case ";${PROMPT_COMMAND:-};" in
+    *";__fn_enter;"*) ;;
+    *) PROMPT_COMMAND="__fn_enter${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac`
+		if shell == "zsh" {
+			install = `autoload -Uz add-zsh-hook
+add-zsh-hook chpwd __fn_enter`
+		}
+		return fmt.Sprintf(`__fn_enter() {
+    [ "$PWD" = "${__fn_enter_pwd-}" ] && return
+    __fn_enter_pwd="$PWD"
+    eval "$(command %[1]s _enter --shell %[2]s --active "${__fn_env_active-}" -- "$PWD")"
+}
+%[3]s
+__fn_enter
+`, binary, shell, install), nil
+	case "fish":
+		return fmt.Sprintf(`function __fn_enter --on-variable PWD
+    set -q __fn_env_active; or set -g __fn_env_active ''
+    command %[1]s _enter --shell fish --active "$__fn_env_active" -- $PWD | source
+end
+__fn_enter
+`, binary), nil
+	}
+	return "", fmt.Errorf("bookmark variables are not supported in %s", shell)
+}
+
+func sortedKeys(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	envCmd.AddCommand(envSetCmd, envUnsetCmd)
+
+	enterCmd.Flags().StringVar(&enterShell, "shell", "", "shell to print code for: bash, zsh or fish")
+	enterCmd.Flags().StringVar(&enterActive, "active", "", "stamp of the variables the shell has applied")
+}
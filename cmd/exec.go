@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <alias> [--] <command> [args...]",
+	Short: "Run a command in a bookmarked directory",
+	Long: `Run a command in a bookmarked directory without leaving the current one.
+The alias is resolved like 'fn <alias>', including fuzzy matches, and the
+use is counted.
+
+The command shares this terminal. Ctrl-C reaches it directly, other signals
+sent to fn are passed on, and fn exits with the command's exit status
+(128+n if it was killed by signal n).`,
+	Example: `  fn exec api -- make test
+  fn exec web npm run build`,
+	Args: cobra.MinimumNArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nameCompletionFunc(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern, command := args[0], args[1:]
+		if command[0] == "--" {
+			command = command[1:]
+		}
+		if len(command) == 0 {
+			return fmt.Errorf("no command given")
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		alias, bookmark, err := resolveBookmark(store, pattern)
+		if err != nil {
+			return err
+		}
+		if storage.IsRemote(bookmark.Path) {
+			return fmt.Errorf("cannot run commands in remote bookmark '%s'", alias)
+		}
+		dir := bookmark.Dir()
+		err = storage.CheckDir(dir)
+		if err != nil {
+			return err
+		}
+
+		store.UpdateUsageWith(alias, resolutionFor(store, alias, pattern))
+
+		// From here on failures are the command's own; it has already
+		// reported them
+		cmd.SilenceUsage = true
+		err = runIn(dir, command)
+		var exitErr *commandExitError
+		if errors.As(err, &exitErr) {
+			cmd.SilenceErrors = true
+		}
+		return err
+	},
+}
+
+// commandExitError carries the exit status of a command run by fn, so that
+// fn exits with the same status
+type commandExitError struct {
+	code int
+}
+
+func (e *commandExitError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.code)
+}
+
+// runIn runs command in dir with this process's stdio, forwarding signals
+// to it, and returns a *commandExitError if it does not succeed
+func runIn(dir string, command []string) error {
+	child := exec.Command(command[0], command[1:]...)
+	child.Dir = dir
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	// The terminal already sends Ctrl-C to the whole foreground process
+	// group, so SIGINT is only caught to keep fn alive until the child exits
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	defer signal.Stop(signals)
+
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig != os.Interrupt {
+					child.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return exitStatus(child.Wait())
+}
+
+// exitStatus turns the error from waiting for a command into a
+// *commandExitError, using 128+n for a command killed by signal n as
+// shells do
+func exitStatus(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return &commandExitError{code: 128 + int(status.Signal())}
+		}
+		return &commandExitError{code: exitErr.ExitCode()}
+	}
+	return err
+}
+
+func init() {
+	// Flags after the alias belong to the command being run
+	execCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestRunIn(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := runIn(dir, []string{"sh", "-c", "pwd > out"}); err != nil {
+		t.Fatalf("runIn failed: %v", err)
+	}
+	out, _ := os.ReadFile(filepath.Join(dir, "out"))
+	if got, _ := filepath.EvalSymlinks(string(out[:len(out)-1])); got != mustEvalSymlinks(t, dir) {
+		t.Errorf("Expected command to run in %s, got %s", dir, out)
+	}
+
+	tests := map[string]int{
+		"exit 7":        7,
+		"kill -TERM $$": 143,
+	}
+	for script, want := range tests {
+		err := runIn(dir, []string{"sh", "-c", script})
+		if got := exitCode(err); got != want {
+			t.Errorf("%q: expected exit code %d, got %d (%v)", script, want, got, err)
+		}
+	}
+}
+
+func TestExecCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("api", tempDir)
+
+	err := execCmd.RunE(execCmd, []string{"ap", "--", "touch", "marker"})
+	if err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "marker")); err != nil {
+		t.Errorf("Expected the command to run in the bookmark: %v", err)
+	}
+
+	store, _ = storage.NewStore()
+	if bookmark, _ := store.GetBookmark("api"); bookmark.UsedCount != 1 {
+		t.Errorf("Expected usage to be recorded, got %d", bookmark.UsedCount)
+	}
+
+	if err := execCmd.RunE(execCmd, []string{"api", "--"}); err == nil {
+		t.Error("Expected an error without a command")
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatalf("Failed to resolve %s: %v", path, err)
+	}
+	return resolved
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/exporter"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat     string
+	exportStripUsage bool
+	exportTags       []string
+	exportPattern    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export bookmarks to JSON, CSV, TOML, YAML or plain text",
+	Long: `Export bookmarks to stdout, or to a file if one is given. The format
+defaults to the file's extension, or JSON.
+
+Use --strip-usage to share a set of bookmarks without your personal usage
+statistics. Exports can be read back with 'fn import --from <format> <file>'.`,
+	Example: `  fn export > bookmarks.json
+  fn export --strip-usage --tag work team.yaml
+  fn export --format csv --pattern api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := ""
+		if len(args) == 1 {
+			file = args[0]
+		}
+
+		format := exportFormat
+		if format == "" {
+			format = exporter.FormatForFile(file)
+		}
+		if format == "" {
+			format = "json"
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		matches, err := store.Query(storage.Query{Tags: exportTags, Pattern: exportPattern})
+		if err != nil {
+			return err
+		}
+		bookmarks := make(map[string]*storage.Bookmark, len(matches))
+		for _, match := range matches {
+			bookmarks[match.Alias] = match.Bookmark
+		}
+
+		out := os.Stdout
+		if file != "" && file != "-" {
+			out, err = os.Create(file)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", file, err)
+			}
+			defer out.Close()
+		}
+
+		err = exporter.Write(out, format, bookmarks, exporter.Options{StripUsage: exportStripUsage, Synonyms: store.GetSynonyms()})
+		if err != nil {
+			return fmt.Errorf("failed to export bookmarks: %w", err)
+		}
+
+		if out != os.Stdout {
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file, err)
+			}
+			color.New(color.FgGreen).Fprintf(os.Stderr, "✓ Exported %d bookmark(s) to %s\n", len(bookmarks), file)
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "output format: "+strings.Join(exporter.Formats, ", "))
+	exportCmd.Flags().BoolVar(&exportStripUsage, "strip-usage", false, "leave out created, used_count and last_used")
+	exportCmd.Flags().StringSliceVar(&exportTags, "tag", nil, "only bookmarks with one of these tags (repeatable)")
+	exportCmd.Flags().StringVar(&exportPattern, "pattern", "", "only bookmarks whose alias or path contains this")
+
+	exportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return exporter.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	foreachTags    []string
+	foreachPattern string
+	foreachJobs    int
+)
+
+var foreachCmd = &cobra.Command{
+	Use:   "foreach [--tag t] [--match pat] [-j N] [--] <command> [args...]",
+	Short: "Run a command in many bookmarked directories in parallel",
+	Long: `Run a command in every selected bookmark directory, at most -j at a time.
+Without --tag or --match every bookmark is selected.
+
+Each line of output is prefixed with the bookmark's alias. The command gets
+no input. When all runs are done a summary of exit codes and durations is
+printed, and fn exits with status 1 if any of them failed or its directory
+no longer exists. Remote bookmarks are skipped.`,
+	Example: `  fn foreach --tag services -- git pull --rebase
+  fn foreach --match api -j 2 -- go test ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if foreachJobs < 1 {
+			return fmt.Errorf("invalid --jobs value: %d (want 1 or more)", foreachJobs)
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		matches, err := store.Query(storage.Query{Tags: foreachTags, Pattern: foreachPattern})
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no bookmarks selected")
+		}
+
+		// Ctrl-C reaches the commands directly; stop starting new ones and
+		// still print the summary
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer stop()
+
+		cmd.SilenceUsage = true
+		results := runForeach(ctx, matches, args, foreachJobs, os.Stdout, os.Stderr)
+		if printForeachSummary(os.Stdout, results) > 0 {
+			cmd.SilenceErrors = true
+			return &commandExitError{code: 1}
+		}
+		return nil
+	},
+}
+
+// foreachResult is the outcome of running the command in one bookmark
+type foreachResult struct {
+	Alias    string
+	Err      error  // nil on success, *commandExitError if the command failed
+	Skipped  string // why the command was not run, if it was not
+	Duration time.Duration
+}
+
+// runForeach runs command in each bookmark directory using at most jobs
+// workers, and returns the results in the order of matches
+func runForeach(ctx context.Context, matches []storage.FuzzyMatch, command []string, jobs int, stdout, stderr io.Writer) []foreachResult {
+	width := 0
+	for _, match := range matches {
+		width = max(width, len(match.Alias))
+	}
+
+	results := make([]foreachResult, len(matches))
+	indexes := make(chan int)
+	var output sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < min(jobs, len(matches)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				match := matches[i]
+				prefix := color.New(color.FgYellow).Sprintf("%-*s", width, match.Alias) + " │ "
+				out := &prefixWriter{mu: &output, out: stdout, prefix: prefix}
+				errOut := &prefixWriter{mu: &output, out: stderr, prefix: prefix}
+				results[i] = runForeachOne(ctx, match, command, out, errOut)
+				out.Flush()
+				errOut.Flush()
+			}
+		}()
+	}
+	for i := range matches {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func runForeachOne(ctx context.Context, match storage.FuzzyMatch, command []string, stdout, stderr io.Writer) foreachResult {
+	result := foreachResult{Alias: match.Alias}
+	if ctx.Err() != nil {
+		result.Skipped = "cancelled"
+		return result
+	}
+	if storage.IsRemote(match.Bookmark.Path) {
+		result.Skipped = "remote"
+		return result
+	}
+	dir := match.Bookmark.Dir()
+	if err := storage.CheckDir(dir); err != nil {
+		result.Err = err
+		return result
+	}
+
+	child := exec.CommandContext(ctx, command[0], command[1:]...)
+	child.Dir = dir
+	child.Stdout = stdout
+	child.Stderr = stderr
+	child.Cancel = func() error {
+		return child.Process.Signal(syscall.SIGTERM)
+	}
+
+	start := time.Now()
+	err := child.Run()
+	result.Duration = time.Since(start)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			err = fmt.Errorf("failed to run %s: %w", command[0], err)
+		}
+		result.Err = exitStatus(err)
+	}
+	return result
+}
+
+// printForeachSummary prints a table of results and returns how many failed
+func printForeachSummary(w io.Writer, results []foreachResult) int {
+	bold := color.New(color.Bold)
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+	gray := color.New(color.FgHiBlack)
+
+	width := len("ALIAS")
+	for _, result := range results {
+		width = max(width, len(result.Alias))
+	}
+
+	failed := 0
+	fmt.Fprintln(w)
+	bold.Fprintf(w, "%-*s  %-10s  %s\n", width, "ALIAS", "STATUS", "DURATION")
+	for _, result := range results {
+		fmt.Fprintf(w, "%-*s  ", width, result.Alias)
+		var exitErr *commandExitError
+		switch {
+		case result.Skipped != "":
+			gray.Fprintf(w, "%-10s  -\n", result.Skipped)
+			continue
+		case result.Err == nil:
+			green.Fprintf(w, "%-10s", "ok")
+		case errors.As(result.Err, &exitErr):
+			failed++
+			red.Fprintf(w, "%-10s", fmt.Sprintf("exit %d", exitErr.code))
+		case errors.Is(result.Err, storage.ErrPathMissing):
+			failed++
+			red.Fprintf(w, "%-10s  -\n", "missing")
+			continue
+		default:
+			failed++
+			red.Fprintf(w, "%-10s  -  %v\n", "error", result.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  %s\n", result.Duration.Round(time.Millisecond))
+	}
+
+	if failed > 0 {
+		red.Fprintf(w, "\n%d of %d failed\n", failed, len(results))
+	}
+	return failed
+}
+
+// prefixWriter writes whole lines to out, each starting with prefix, so
+// lines from commands running side by side do not mix
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes out a final line that did not end in a newline
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) emit(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
+
+func init() {
+	// Flags after the command belong to it
+	foreachCmd.Flags().SetInterspersed(false)
+	foreachCmd.Flags().StringSliceVar(&foreachTags, "tag", nil, "only bookmarks with one of these tags (repeatable)")
+	foreachCmd.Flags().StringVar(&foreachPattern, "match", "", "only bookmarks whose alias or path contains this")
+	foreachCmd.Flags().IntVarP(&foreachJobs, "jobs", "j", runtime.NumCPU(), "run at most this many commands at once")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestRunForeach(t *testing.T) {
+	disableColor(t)
+	root := t.TempDir()
+	matches := []storage.FuzzyMatch{
+		{Alias: "api", Bookmark: &storage.Bookmark{Path: filepath.Join(root, "api")}},
+		{Alias: "web", Bookmark: &storage.Bookmark{Path: filepath.Join(root, "web")}},
+		{Alias: "gone", Bookmark: &storage.Bookmark{Path: filepath.Join(root, "gone")}},
+		{Alias: "box", Bookmark: &storage.Bookmark{Path: "ssh://box/srv"}},
+	}
+	os.Mkdir(filepath.Join(root, "api"), 0755)
+	os.Mkdir(filepath.Join(root, "web"), 0755)
+	os.WriteFile(filepath.Join(root, "web", "broken"), nil, 0644)
+
+	var stdout, stderr bytes.Buffer
+	script := `echo "in $(basename "$PWD")"; printf partial; test ! -f broken || { echo bad >&2; exit 4; }`
+	results := runForeach(context.Background(), matches, []string{"sh", "-c", script}, 2, &stdout, &stderr)
+
+	for _, want := range []string{"api  │ in api\n", "api  │ partial\n", "web  │ in web\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected stdout to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+	if stderr.String() != "web  │ bad\n" {
+		t.Errorf("Expected prefixed stderr, got %q", stderr.String())
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("Expected api to succeed, got %v", results[0].Err)
+	}
+	var exitErr *commandExitError
+	if !errors.As(results[1].Err, &exitErr) || exitErr.code != 4 {
+		t.Errorf("Expected web to exit 4, got %v", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, storage.ErrPathMissing) {
+		t.Errorf("Expected gone to be missing, got %v", results[2].Err)
+	}
+	if results[3].Skipped != "remote" {
+		t.Errorf("Expected box to be skipped, got %+v", results[3])
+	}
+
+	var summary bytes.Buffer
+	if failed := printForeachSummary(&summary, results); failed != 2 {
+		t.Errorf("Expected 2 failures, got %d", failed)
+	}
+	for _, want := range []string{"api    ok", "web    exit 4", "gone   missing", "box    remote", "2 of 4 failed"} {
+		if !strings.Contains(summary.String(), want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, summary.String())
+		}
+	}
+}
+
+func TestRunForeachCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	matches := []storage.FuzzyMatch{{Alias: "api", Bookmark: &storage.Bookmark{Path: t.TempDir()}}}
+	results := runForeach(ctx, matches, []string{"true"}, 1, &bytes.Buffer{}, &bytes.Buffer{})
+	if results[0].Skipped != "cancelled" {
+		t.Errorf("Expected the run to be cancelled, got %+v", results[0])
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &out, prefix: "> "}
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	if out.String() != "> one\n> two\n" {
+		t.Errorf("Expected only whole lines before Flush, got %q", out.String())
+	}
+	w.Flush()
+	if out.String() != "> one\n> two\n> three\n" {
+		t.Errorf("Expected the last line after Flush, got %q", out.String())
+	}
+}
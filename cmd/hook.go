@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage shell snippets that run on entering a bookmark",
+	Long: `Give a bookmark on-enter hooks: shell snippets the shell wrapper runs right
+after 'fn <alias>' has changed into its directory, such as activating a
+virtualenv or switching the Node version.
+
+Hooks, like the variables set with 'fn env set' and the tasks added with
+'fn task add', only take effect once they are allowed on this machine. Ones you set yourself are allowed straight away;
+ones that arrive through 'fn import', 'fn merge' or 'fn sync', or whose
+bookmark was re-pointed, must be reviewed with 'fn hook show' and allowed
+with 'fn hook allow'. Until then fn warns and skips them.
+
+Hooks need the bash, zsh or fish wrapper from 'fn init'.`,
+	Example: `  fn hook set api 'source .venv/bin/activate'
+  fn hook set web 'nvm use' 'kubectx staging'
+  fn hook show
+  fn hook allow api`,
+}
+
+var hookSetCmd = &cobra.Command{
+	Use:   "set <alias> [snippet...]",
+	Short: "Set the on-enter hooks of a bookmark",
+	Long: `Replace the on-enter hooks of a bookmark. Each argument is one snippet;
+without any, a single snippet is read from stdin. The new hooks are allowed
+to run on this machine, unless the bookmark still has variables from
+elsewhere that are waiting to be allowed.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias, hooks := args[0], args[1:]
+		if len(hooks) == 0 {
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				return fmt.Errorf("give the hooks as arguments or on stdin")
+			}
+			input, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read hook: %w", err)
+			}
+			if hook := strings.TrimSpace(string(input)); hook != "" {
+				hooks = []string{hook}
+			}
+		}
+		if len(hooks) == 0 {
+			return fmt.Errorf("no hooks given; use 'fn hook clear %s' to remove them", alias)
+		}
+		// An empty snippet would leave nothing after the hooks marker
+		for _, hook := range hooks {
+			if strings.TrimSpace(hook) == "" {
+				return fmt.Errorf("empty hook given; use 'fn hook clear %s' to remove the hooks", alias)
+			}
+		}
+
+		return updateHooks(alias, hooks)
+	},
+}
+
+var hookClearCmd = &cobra.Command{
+	Use:               "clear <alias>",
+	Short:             "Remove the on-enter hooks of a bookmark",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateHooks(args[0], nil)
+	},
+}
+
+var hookShowCmd = &cobra.Command{
+	Use:               "show [alias]",
+	Short:             "Show hooks, variables and tasks, and whether they are allowed",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		trust, err := storage.LoadTrust()
+		if err != nil {
+			return err
+		}
+
+		bookmarks := store.GetAllBookmarks()
+		aliases := sortedAliases(bookmarks)
+		if len(args) == 1 {
+			if _, exists := bookmarks[args[0]]; !exists {
+				return fmt.Errorf("%w: %s", storage.ErrNotFound, args[0])
+			}
+			aliases = args[:1]
+		}
+
+		shown := 0
+		for _, alias := range aliases {
+			bookmark := bookmarks[alias]
+			if !needsTrust(bookmark) {
+				continue
+			}
+			shown++
+			fmt.Printf("%s → %s ", color.YellowString(alias), bookmark.Path)
+			if trust.Trusted(alias, bookmark) {
+				color.Green("(allowed)")
+			} else {
+				color.Red("(not allowed)")
+			}
+			for _, hook := range bookmark.Hooks {
+				fmt.Println("  " + strings.ReplaceAll(hook, "\n", "\n  "))
+			}
+			for _, key := range sortedKeys(bookmark.Env) {
+				fmt.Printf("  %s %s=%s\n", color.CyanString("env"), key, bookmark.Env[key])
+			}
+			for _, name := range sortedKeys(bookmark.Tasks) {
+				fmt.Printf("  %s %s: %s\n", color.CyanString("task"), name, strings.ReplaceAll(bookmark.Tasks[name], "\n", "\n  "))
+			}
+		}
+		if shown == 0 {
+			fmt.Println("No hooks, variables or tasks set")
+		}
+		return nil
+	},
+}
+
+var hookAllowCmd = &cobra.Command{
+	Use:               "allow <alias>",
+	Short:             "Allow the current hooks, variables and tasks of a bookmark",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		bookmark, exists := store.GetBookmark(args[0])
+		if !exists {
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, args[0])
+		}
+		if !needsTrust(bookmark) {
+			return fmt.Errorf("'%s' has no hooks, variables or tasks", args[0])
+		}
+
+		trust, err := storage.LoadTrust()
+		if err != nil {
+			return err
+		}
+		if err := trust.Allow(args[0], bookmark); err != nil {
+			return fmt.Errorf("failed to allow hooks: %w", err)
+		}
+		color.Green("✓ Allowed hooks, variables and tasks of '%s'", args[0])
+		return nil
+	},
+}
+
+var hookDenyCmd = &cobra.Command{
+	Use:               "deny <alias>",
+	Short:             "Stop the hooks, variables and tasks of a bookmark from taking effect",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		trust, err := storage.LoadTrust()
+		if err != nil {
+			return err
+		}
+		if err := trust.Deny(args[0]); err != nil {
+			return fmt.Errorf("failed to deny hooks: %w", err)
+		}
+		color.Green("✓ Hooks, variables and tasks of '%s' will no longer take effect", args[0])
+		return nil
+	},
+}
+
+// updateHooks replaces a bookmark's hooks
+func updateHooks(alias string, hooks []string) error {
+	allowed, err := updateOnEnter(alias, func(store *storage.Store) error {
+		return store.SetHooks(alias, hooks)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(hooks) == 0 {
+		color.Green("✓ Removed hooks of '%s'", alias)
+	} else {
+		color.Green("✓ Set %d hook(s) for '%s'", len(hooks), alias)
+	}
+	warnNotAllowed(alias, allowed)
+	return nil
+}
+
+// updateOnEnter applies a change to what a bookmark does on entering it and
+// records the result as allowed, since the user has just written it. That
+// only happens if the bookmark was allowed before, so editing one part never
+// approves another part that arrived unreviewed.
+func updateOnEnter(alias string, update func(*storage.Store) error) (bool, error) {
+	store, err := storage.NewStore()
+	if err != nil {
+		return false, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	trust, err := storage.LoadTrust()
+	if err != nil {
+		return false, err
+	}
+
+	bookmark, exists := store.GetBookmark(alias)
+	if !exists {
+		return false, fmt.Errorf("%w: %s", storage.ErrNotFound, alias)
+	}
+	allowed := !needsTrust(bookmark) || trust.Trusted(alias, bookmark)
+
+	if err := update(store); err != nil {
+		return false, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+
+	switch {
+	case !needsTrust(bookmark):
+		err = trust.Deny(alias)
+	case allowed:
+		err = trust.Allow(alias, bookmark)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update allowed hooks: %w", err)
+	}
+	return allowed || !needsTrust(bookmark), nil
+}
+
+// needsTrust reports whether the bookmark has hooks, variables or tasks,
+// which only take effect once allowed
+func needsTrust(bookmark *storage.Bookmark) bool {
+	return len(bookmark.Hooks) > 0 || len(bookmark.Env) > 0 || len(bookmark.Tasks) > 0
+}
+
+// warnNotAllowed reminds the user that parts they did not write still need
+// reviewing
+func warnNotAllowed(alias string, allowed bool) {
+	if !allowed {
+		color.Yellow("'%s' has hooks, variables or tasks that are not allowed yet; review them with 'fn hook show %s', then run 'fn hook allow %s'", alias, alias, alias)
+	}
+}
+
+func init() {
+	hookCmd.AddCommand(hookSetCmd, hookClearCmd, hookShowCmd, hookAllowCmd, hookDenyCmd)
+}
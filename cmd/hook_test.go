@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestHooksInWrapper(t *testing.T) {
+	// Build before changing HOME, which would also move the build cache
+	binDir := t.TempDir()
+	build := exec.Command("go", "build", "-o", filepath.Join(binDir, "fn"), "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, out)
+	}
+
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	project := filepath.Join(tempDir, "project")
+	os.Mkdir(project, 0755)
+	store, _ := storage.NewStore()
+	store.SaveBookmark("project", project)
+	if err := updateHooks("project", []string{`echo "hook in $PWD"`, "HOOKED=yes"}); err != nil {
+		t.Fatalf("Failed to set hooks: %v", err)
+	}
+
+	wrapper, err := shellInit("bash", "fn", "fn", rootCmd, false)
+	if err != nil {
+		t.Fatalf("shellInit failed: %v", err)
+	}
+	run := func() (string, string) {
+		t.Helper()
+		shell := exec.Command("bash", "-c", wrapper+`fn project && echo "HOOKED=${HOOKED-}"`)
+		shell.Env = append(os.Environ(), "PATH="+binDir+":"+os.Getenv("PATH"))
+		var stderr strings.Builder
+		shell.Stderr = &stderr
+		out, err := shell.Output()
+		if err != nil {
+			t.Fatalf("Wrapper failed: %v\n%s", err, stderr.String())
+		}
+		return string(out), stderr.String()
+	}
+
+	out, _ := run()
+	if out != "hook in "+project+"\nHOOKED=yes\n" {
+		t.Errorf("Expected allowed hooks to run after cd, got %q", out)
+	}
+
+	// Hooks changed behind fn's back, as by an import, must not run
+	store, _ = storage.NewStore()
+	store.SetHooks("project", []string{"HOOKED=sneaky"})
+	out, stderr := run()
+	if out != "HOOKED=\n" {
+		t.Errorf("Expected changed hooks not to run, got %q", out)
+	}
+	if !strings.Contains(stderr, "fn hook allow project") {
+		t.Errorf("Expected a warning about unallowed hooks, got %q", stderr)
+	}
+
+	// A newline in a path must not let its tail run as shell code
+	evil := filepath.Join(tempDir, "a\necho INJECTED")
+	os.Mkdir(evil, 0755)
+	store.SaveBookmark("evil", evil)
+	shell := exec.Command("bash", "-c", wrapper+"fn evil")
+	shell.Env = append(os.Environ(), "PATH="+binDir+":"+os.Getenv("PATH"))
+	injected, _ := shell.Output()
+	if strings.Contains(string(injected), "INJECTED") {
+		t.Errorf("Expected a path with a newline not to be evaluated, got %q", injected)
+	}
+}
+
+func TestHookSetRejectsEmpty(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("a", t.TempDir())
+
+	for _, hooks := range [][]string{{""}, {"make", "  "}} {
+		if err := hookSetCmd.RunE(hookSetCmd, append([]string{"a"}, hooks...)); err == nil {
+			t.Errorf("Expected hooks %q to be rejected", hooks)
+		}
+	}
+	store, _ = storage.NewStore()
+	if bookmark, _ := store.GetBookmark("a"); len(bookmark.Hooks) != 0 {
+		t.Errorf("Expected no hooks to be saved, got %q", bookmark.Hooks)
+	}
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/exporter"
+	"github.com/rethil/fast-nav/internal/importer"
+	"github.com/rethil/fast-nav/internal/remote"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFrom     string
+	importDryRun   bool
+	importConflict string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [--from <tool>] [file]",
+	Short: "Import bookmarks from other jump tools",
+	Long: `Import directories from zoxide, autojump, z, fasd, bashmarks, or
+alias x='cd /path' lines in a shell rc file. Files written by 'fn export'
+are read with --from json, csv, toml, yaml or txt, which is the default
+for files with those extensions. Synonyms in JSON, TOML and YAML exports
+are imported with their bookmark unless the name is already taken.
+
+The file defaults to the tool's usual location; use - to read stdin.
+Tools that only store paths get aliases derived from the directory name,
+and their scores become usage counts. Directories that no longer exist,
+and path-only entries that are already bookmarked, are skipped.
+
+--conflict decides what happens when an imported alias already exists:
+  skip       keep the existing bookmark (default)
+  overwrite  replace it with the imported one
+  rename     import under a new alias such as proj-2`,
+	Example: `  fn import --from zoxide --dry-run
+  fn import --from z ~/.z
+  fn import --from shell-aliases ~/.zshrc --conflict rename`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importConflict != "skip" && importConflict != "overwrite" && importConflict != "rename" {
+			return fmt.Errorf("invalid --conflict value: %s (want skip, overwrite or rename)", importConflict)
+		}
+
+		file := ""
+		if len(args) == 1 {
+			file = args[0]
+			if importFrom == "" {
+				importFrom = exporter.FormatForFile(file)
+			}
+		}
+		if importFrom == "" {
+			return fmt.Errorf("--from is required (one of %s)", strings.Join(importer.Sources, ", "))
+		}
+		if file == "" {
+			path, err := importer.DefaultPath(importFrom)
+			if err != nil {
+				return err
+			}
+			file = path
+		}
+
+		entries, err := readImport(importFrom, file)
+		if err != nil {
+			return err
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		plan := planImport(store, entries, importConflict)
+		printImportPlan(os.Stdout, plan, importDryRun)
+		if importDryRun {
+			return nil
+		}
+
+		return store.Batch(func() error {
+			for _, item := range plan {
+				if item.action != importAdd && item.action != importOverwrite {
+					continue
+				}
+				err := store.PutBookmark(item.alias, item.bookmark)
+				if err != nil {
+					return fmt.Errorf("failed to save bookmark '%s': %w", item.alias, err)
+				}
+			}
+			for _, item := range plan {
+				for _, synonym := range item.synonyms {
+					if err := store.AddSynonym(item.alias, synonym); err != nil {
+						return fmt.Errorf("failed to save synonym '%s': %w", synonym, err)
+					}
+				}
+			}
+			return nil
+		})
+	},
+}
+
+func readImport(source, file string) ([]importer.Entry, error) {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	entries, err := importer.Parse(source, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return entries, nil
+}
+
+type importAction string
+
+const (
+	importAdd       importAction = "add"
+	importOverwrite importAction = "overwrite"
+	importSkip      importAction = "skip"
+)
+
+// importItem is what will happen to one imported entry
+type importItem struct {
+	entry    importer.Entry
+	alias    string
+	bookmark *storage.Bookmark
+	synonyms []string // the entry's synonyms that are free to import
+	action   importAction
+	reason   string // why an entry is skipped or renamed
+}
+
+// planImport decides an alias and action for every entry without touching
+// the store
+func planImport(store *storage.Store, entries []importer.Entry, conflict string) []importItem {
+	now := time.Now()
+	claimed := make(map[string]bool)
+	bookmarkedPaths := make(map[string]bool)
+	for _, bookmark := range store.GetAllBookmarks() {
+		bookmarkedPaths[cleanImportPath(bookmark.Path)] = true
+	}
+	synonyms := store.GetSynonyms()
+	taken := func(alias string) bool {
+		_, exists := store.GetBookmark(alias)
+		_, synonym := synonyms[alias]
+		return exists || synonym || claimed[alias]
+	}
+
+	var plan []importItem
+	for _, entry := range entries {
+		item := importItem{entry: entry, action: importAdd}
+		path := cleanImportPath(entry.Path)
+
+		switch {
+		case unusablePath(path, entry.File) != "":
+			item.action, item.reason = importSkip, unusablePath(path, entry.File)
+		case entry.Alias == "":
+			if bookmarkedPaths[path] {
+				item.action, item.reason = importSkip, "already bookmarked"
+				break
+			}
+			item.alias = proposeAlias(path, taken)
+			if item.alias == "" {
+				item.action, item.reason = importSkip, "no usable alias"
+			}
+		case !isValidAlias(entry.Alias):
+			item.alias = proposeAlias(path, taken)
+			item.reason = fmt.Sprintf("'%s' is not a valid alias", entry.Alias)
+			if item.alias == "" {
+				item.action = importSkip
+			}
+		case claimed[entry.Alias]:
+			// The same source defined the alias twice; the first one wins
+			item.action, item.reason = importSkip, "duplicate in import"
+		case taken(entry.Alias):
+			_, synonym := synonyms[entry.Alias]
+			switch {
+			case synonym && conflict != "rename":
+				// A bookmark must never shadow a synonym, even with overwrite
+				item.action, item.reason = importSkip, fmt.Sprintf("'%s' is a synonym of '%s'", entry.Alias, synonyms[entry.Alias])
+			case conflict == "overwrite":
+				item.alias, item.action = entry.Alias, importOverwrite
+			case conflict == "rename":
+				item.alias = numberedAlias(entry.Alias, taken)
+				item.reason = fmt.Sprintf("'%s' already exists", entry.Alias)
+				if item.alias == "" {
+					item.action = importSkip
+				}
+			default:
+				item.action, item.reason = importSkip, "alias already exists"
+			}
+		default:
+			item.alias = entry.Alias
+		}
+
+		if item.action != importSkip {
+			claimed[item.alias] = true
+			bookmarkedPaths[path] = true
+			item.bookmark = &storage.Bookmark{
+				Path:      path,
+				Created:   now,
+				UsedCount: entry.UseCount(),
+				LastUsed:  entry.LastUsed,
+				Tags:      entry.Tags,
+				File:      entry.File,
+				Opener:    entry.Opener,
+				Hooks:     entry.Hooks,
+				Env:       entry.Env,
+				Tasks:     entry.Tasks,
+			}
+			if !entry.Created.IsZero() {
+				item.bookmark.Created = entry.Created
+			}
+			if item.bookmark.LastUsed.IsZero() {
+				item.bookmark.LastUsed = now
+			}
+		}
+		plan = append(plan, item)
+	}
+
+	// Synonyms come last, so they never take a name an imported alias wants
+	for i := range plan {
+		if plan[i].action == importSkip {
+			continue
+		}
+		for _, synonym := range plan[i].entry.Synonyms {
+			if isValidAlias(synonym) && !taken(synonym) {
+				claimed[synonym] = true
+				plan[i].synonyms = append(plan[i].synonyms, synonym)
+			}
+		}
+	}
+
+	return plan
+}
+
+// cleanImportPath cleans local paths; remote targets are left as they are,
+// since cleaning would collapse the // after ssh:
+func cleanImportPath(path string) string {
+	if storage.IsRemote(path) {
+		return path
+	}
+	return filepath.Clean(path)
+}
+
+// unusablePath says why path cannot be bookmarked, or returns "" if it can.
+// Remote targets are not checked over ssh.
+func unusablePath(path string, file bool) string {
+	switch {
+	case storage.IsRemote(path):
+		if _, err := remote.Parse(path); err != nil {
+			return "invalid remote target"
+		}
+	case !filepath.IsAbs(path):
+		return "not an absolute path"
+	case file && !isFile(path):
+		return "file does not exist"
+	case !file && storage.CheckDir(path) != nil:
+		return "directory does not exist"
+	}
+	return ""
+}
+
+// isFile reports whether path exists and is not a directory
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// numberedAlias appends -2, -3, ... to alias until it is free
+func numberedAlias(alias string, taken func(string) bool) string {
+	for i := 2; i < 100; i++ {
+		candidate := fmt.Sprintf("%s-%d", alias, i)
+		if isValidAlias(candidate) && !taken(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func printImportPlan(w io.Writer, plan []importItem, dryRun bool) {
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	gray := color.New(color.FgHiBlack)
+
+	counts := make(map[importAction]int)
+	for _, item := range plan {
+		counts[item.action]++
+		switch item.action {
+		case importAdd:
+			green.Fprintf(w, "+ %-15s", item.alias)
+			fmt.Fprintf(w, " → %s (used %d times)%s", item.bookmark.Path, item.bookmark.UsedCount, alsoKnownAs(item.synonyms))
+		case importOverwrite:
+			yellow.Fprintf(w, "~ %-15s", item.alias)
+			fmt.Fprintf(w, " → %s (overwrites existing)%s", item.bookmark.Path, alsoKnownAs(item.synonyms))
+		case importSkip:
+			name := item.entry.Alias
+			if name == "" {
+				name = "-"
+			}
+			gray.Fprintf(w, "= %-15s → %s", name, item.entry.Path)
+		}
+		if item.reason != "" {
+			gray.Fprintf(w, " [%s]", item.reason)
+		}
+		fmt.Fprintln(w)
+	}
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	summary := fmt.Sprintf("%s %d bookmark(s)", verb, counts[importAdd]+counts[importOverwrite])
+	if counts[importOverwrite] > 0 {
+		summary += fmt.Sprintf(", overwriting %d", counts[importOverwrite])
+	}
+	if counts[importSkip] > 0 {
+		summary += fmt.Sprintf(", skipped %d", counts[importSkip])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, summary)
+	if dryRun {
+		gray.Fprintln(w, "Dry run: nothing was saved.")
+	}
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "", "source format: "+strings.Join(importer.Sources, ", "))
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show what would be imported without saving")
+	importCmd.Flags().StringVar(&importConflict, "conflict", "skip", "when an alias exists: skip, overwrite or rename")
+
+	importCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return importer.Sources, cobra.ShellCompDirectiveNoFileComp
+	})
+	importCmd.RegisterFlagCompletionFunc("conflict", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"skip", "overwrite", "rename"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
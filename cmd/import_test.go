@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/importer"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestPlanImport(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	api := filepath.Join(tempDir, "src", "api")
+	web := filepath.Join(tempDir, "src", "web")
+	docs := filepath.Join(tempDir, "docs")
+	for _, dir := range []string{api, web, docs} {
+		os.MkdirAll(dir, 0755)
+	}
+
+	store, err := storage.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	store.SaveBookmark("api", api)
+
+	t.Run("PathOnly", func(t *testing.T) {
+		plan := planImport(store, []importer.Entry{
+			{Path: api, Score: 10},
+			{Path: web, Score: 2.4},
+			{Path: filepath.Join(tempDir, "gone"), Score: 1},
+		}, "skip")
+
+		if plan[0].action != importSkip || plan[0].reason != "already bookmarked" {
+			t.Errorf("Expected bookmarked path to be skipped, got %+v", plan[0])
+		}
+		if plan[1].action != importAdd || plan[1].alias != "web" || plan[1].bookmark.UsedCount != 2 {
+			t.Errorf("Expected web to be added with 2 uses, got %+v", plan[1])
+		}
+		if plan[2].action != importSkip {
+			t.Errorf("Expected missing directory to be skipped, got %+v", plan[2])
+		}
+	})
+
+	conflicts := []importer.Entry{{Alias: "api", Path: docs}, {Alias: "api", Path: web}}
+	tests := []struct {
+		policy string
+		action importAction
+		alias  string
+	}{
+		{"skip", importSkip, ""},
+		{"overwrite", importOverwrite, "api"},
+		{"rename", importAdd, "api-2"},
+	}
+	for _, tt := range tests {
+		t.Run("Conflict-"+tt.policy, func(t *testing.T) {
+			plan := planImport(store, conflicts, tt.policy)
+			if plan[0].action != tt.action || plan[0].alias != tt.alias {
+				t.Errorf("Expected %s %q, got %s %q", tt.action, tt.alias, plan[0].action, plan[0].alias)
+			}
+		})
+	}
+
+	t.Run("InvalidAlias", func(t *testing.T) {
+		plan := planImport(store, []importer.Entry{{Alias: "list", Path: docs}}, "skip")
+		if plan[0].action != importAdd || plan[0].alias != "docs" {
+			t.Errorf("Expected reserved alias to be replaced by 'docs', got %+v", plan[0])
+		}
+	})
+
+	t.Run("Synonyms", func(t *testing.T) {
+		store.AddSynonym("api", "backend")
+		defer store.RemoveSynonym("backend")
+
+		plan := planImport(store, []importer.Entry{
+			{Alias: "backend", Path: docs},
+			{Alias: "web", Path: web, Synonyms: []string{"frontend", "backend", "api", "site"}},
+			{Alias: "site", Path: docs},
+		}, "overwrite")
+		if plan[0].action != importSkip {
+			t.Errorf("Expected an alias that is a synonym to be skipped, got %+v", plan[0])
+		}
+		if plan[1].action != importAdd || strings.Join(plan[1].synonyms, ",") != "frontend" {
+			t.Errorf("Expected only the free synonym to be imported, got %+v", plan[1])
+		}
+		if plan[2].action != importAdd || plan[2].alias != "site" {
+			t.Errorf("Expected an imported alias to win over a synonym, got %+v", plan[2])
+		}
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		plan := planImport(store, []importer.Entry{
+			{Alias: "build", Path: "ssh://me@build1/srv/100%25"},
+			{Alias: "broken", Path: "ssh:///srv"},
+		}, "skip")
+		if plan[0].action != importAdd || plan[0].bookmark.Path != "ssh://me@build1/srv/100%25" {
+			t.Errorf("Expected the remote bookmark to be imported as is, got %+v", plan[0])
+		}
+		if plan[1].action != importSkip {
+			t.Errorf("Expected an invalid remote target to be skipped, got %+v", plan[1])
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cdAnnotation marks commands whose output is a directory for the shell
+// wrapper to cd into
+const cdAnnotation = "fn/cd"
+
+var (
+	initCmdName  string
+	initNoRecord bool
+	initEnv      bool
+)
+
+var validFunctionName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+var initCmd = &cobra.Command{
+	Use:   "init [bash|zsh|fish|tcsh]",
+	Short: "Print shell integration code",
+	Long: `Print the shell function that wraps fn, generated from this binary's
+command list so it always matches the installed version.
+
+Bash (~/.bashrc):
+  eval "$(fn init bash)"
+
+Zsh (~/.zshrc):
+  eval "$(fn init zsh)"
+
+Fish (~/.config/fish/config.fish):
+  fn init fish | source
+
+Tcsh (~/.tcshrc):
+  fn init tcsh > ~/.fn.tcsh
+  source ~/.fn.tcsh
+
+Use --cmd to give the shell function a different name, e.g. --cmd j.
+With --env, bookmarks also become named directories (zsh), variables (bash)
+or abbreviations (fish), kept up to date before each prompt; see 'fn env'.`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "tcsh"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !validFunctionName.MatchString(initCmdName) {
+			return fmt.Errorf("invalid function name: %s", initCmdName)
+		}
+
+		script, err := shellInit(args[0], initCmdName, binaryName(), cmd.Root(), !initNoRecord)
+		if err != nil {
+			return err
+		}
+		if initEnv {
+			hook, err := envHook(args[0], binaryName())
+			if err != nil {
+				return err
+			}
+			script += "\n" + hook
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// commandRoutes splits the command tree into commands the wrapper runs
+// directly and commands whose output it cds into
+func commandRoutes(root *cobra.Command) (passthrough, cd []string) {
+	root.InitDefaultHelpCmd()
+
+	seen := make(map[string]bool)
+	for _, c := range root.Commands() {
+		names := append([]string{c.Name()}, c.Aliases...)
+		for _, name := range names {
+			if seen[name] || strings.HasPrefix(name, "<") {
+				continue
+			}
+			seen[name] = true
+			if c.Annotations[cdAnnotation] == "true" {
+				cd = append(cd, name)
+			} else {
+				passthrough = append(passthrough, name)
+			}
+		}
+	}
+
+	sort.Strings(passthrough)
+	sort.Strings(cd)
+	return passthrough, cd
+}
+
+// shellInit renders the wrapper function (and optionally the visit-recording
+// hook) for the given shell
+func shellInit(shell, function, binary string, root *cobra.Command, record bool) (string, error) {
+	passthrough, cd := commandRoutes(root)
+	// 'fn -' is rewritten to 'fn back' by Execute, so it also needs a cd
+	cd = append([]string{"-"}, cd...)
+
+	var script string
+	switch shell {
+	case "bash", "zsh":
+		script = posixInit(shell, function, binary, passthrough, cd)
+	case "fish":
+		script = fishInit(function, binary, passthrough, cd)
+	case "tcsh":
+		script = tcshInit(function, binary, passthrough, cd)
+	default:
+		return "", fmt.Errorf("unsupported shell: %s", shell)
+	}
+
+	if record {
+		hook, err := recordHook(shell, binary)
+		if err != nil {
+			return "", err
+		}
+		script += "\n" + hook
+	}
+
+	// tcsh has no way to run code on every directory change
+	if shell != "tcsh" {
+		hook, err := enterHook(shell, binary)
+		if err != nil {
+			return "", err
+		}
+		script += "\n" + hook
+	}
+
+	return script, nil
+}
+
+func posixInit(shell, function, binary string, passthrough, cd []string) string {
+	return fmt.Sprintf(`# fn shell integration (generated by '%[2]s init %[1]s')
+%[3]s() {
+    case "${1-}" in
+        %[5]s)
+            __%[3]s_cd "$@"
+            ;;
+        ""|-?*|%[4]s)
+            FN_SESSION=$$ command %[2]s "$@"
+            ;;
+        *)
+            __%[3]s_cd navigate "$@"
+            ;;
+    esac
+}
+
+__%[3]s_cd() {
+    local __fn_out __fn_dir
+    __fn_out="$(FN_SESSION=$$ FN_HOOKS=1 command %[2]s "$@")" || return $?
+    # The directory may be followed by a marker line and on-enter hooks
+    __fn_dir="${__fn_out%%%%
+%[6]s
+*}"
+    if [ -d "$__fn_dir" ]; then
+        cd -- "$__fn_dir" || return $?
+        __fn_enter
+        if [ "$__fn_dir" != "$__fn_out" ]; then
+            eval "${__fn_out#*
+%[6]s
+}"
+        fi
+    elif [ "${__fn_out#ssh://}" != "$__fn_out" ]; then
+        command %[2]s _ssh "$__fn_out"
+    elif [ -n "$__fn_out" ]; then
+        printf '%%s\n' "$__fn_out"
+    fi
+}
+`, shell, binary, function, strings.Join(passthrough, "|"), strings.Join(cd, "|"), hooksMarker)
+}
+
+func fishInit(function, binary string, passthrough, cd []string) string {
+	quote := func(names []string) string {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = "'" + name + "'"
+		}
+		return strings.Join(quoted, " ")
+	}
+
+	return fmt.Sprintf(`# fn shell integration (generated by '%[1]s init fish')
+function %[2]s
+    set -lx FN_SESSION $fish_pid
+    switch "$argv[1]"
+        case %[4]s
+            __%[2]s_cd $argv
+        case '' '-?*' %[3]s
+            command %[1]s $argv
+        case '*'
+            __%[2]s_cd navigate $argv
+    end
+end
+
+function __%[2]s_cd
+    set -l out (FN_HOOKS=1 command %[1]s $argv)
+    set -l code $status
+    test $code -eq 0; or return $code
+    # The directory may be followed by a marker line and on-enter hooks
+    set -l hooks
+    if set -l i (contains -i -- '%[5]s' $out)
+        test $i -lt (count $out); and set hooks $out[(math $i + 1)..-1]
+        set out $out[1..(math $i - 1)]
+    end
+    if test (count $out) -eq 1 -a -d "$out[1]"
+        cd -- $out[1]; or return $status
+        if test (count $hooks) -gt 0
+            string join \n -- $hooks | source
+        end
+    else if test (count $out) -eq 1; and string match -q 'ssh://*' -- $out[1]
+        command %[1]s _ssh $out[1]
+    else if test (count $out) -gt 0
+        printf '%%s\n' $out
+    end
+end
+`, binary, function, quote(passthrough), quote(cd), hooksMarker)
+}
+
+// tcshInit builds an alias, since tcsh has no functions. One-line ifs expand
+// backquotes eagerly, so the capturing call is kept in a variable and eval'd.
+func tcshInit(function, binary string, passthrough, cd []string) string {
+	matches := func(names []string) string {
+		conds := make([]string, len(names))
+		for i, name := range names {
+			conds[i] = fmt.Sprintf(`"$__fn_first" == "%s"`, name)
+		}
+		return strings.Join(conds, " || ")
+	}
+
+	return fmt.Sprintf(`# fn shell integration (generated by '%[1]s init tcsh')
+set __fn_capture = 'set __fn_out = "`+"`env FN_SESSION=$$ %[1]s $__fn_argv:q`"+`"'
+alias %[2]s 'set __fn_first = ( \!*:q "" ); set __fn_first = "$__fn_first[1]"; set __fn_route = navigate; set __fn_out = ""; if ( %[4]s ) set __fn_route = cd; if ( "$__fn_first" == "" || "$__fn_first" =~ -?* || %[3]s ) set __fn_route = run; if ( $__fn_route == run ) env FN_SESSION=$$ %[1]s \!*:q; set __fn_argv = ( \!*:q ); if ( $__fn_route == navigate ) set __fn_argv = ( navigate \!*:q ); if ( $__fn_route != run ) eval $__fn_capture:q; if ( -d "$__fn_out" ) cd "$__fn_out"; if ( "$__fn_out" =~ ssh://* ) env %[1]s _ssh "$__fn_out"; if ( ! -d "$__fn_out" && "$__fn_out" != "" && "$__fn_out" !~ ssh://* ) printf "%%s\n" $__fn_out:q'
+`, binary, function, matches(passthrough), matches(cd))
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initCmdName, "cmd", "fn", "name of the shell function to define")
+	initCmd.Flags().BoolVar(&initNoRecord, "no-record", false, "do not install the hook that records visited directories")
+	initCmd.Flags().BoolVar(&initEnv, "env", false, "also export bookmarks to the shell and refresh them when they change")
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCommandRoutes(t *testing.T) {
+	passthrough, cd := commandRoutes(rootCmd)
+
+	contains := func(names []string, name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range []string{"navigate", "recent", "r", "back", "forward"} {
+		if !contains(cd, name) {
+			t.Errorf("Expected %q to be a cd command, got %v", name, cd)
+		}
+	}
+	for _, name := range []string{"save", "list", "help", "init", "stack"} {
+		if !contains(passthrough, name) {
+			t.Errorf("Expected %q to be passed through, got %v", name, passthrough)
+		}
+	}
+	if contains(passthrough, "navigate") {
+		t.Error("navigate should not be passed through")
+	}
+}
+
+func TestShellInitSyntax(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		script, err := shellInit(shell, "j", "fn", rootCmd, true)
+		if err != nil {
+			t.Fatalf("shellInit(%s) failed: %v", shell, err)
+		}
+		if !strings.Contains(script, "j() {") {
+			t.Errorf("Expected %s script to define j()", shell)
+		}
+
+		check := exec.Command("bash", "-n")
+		check.Stdin = strings.NewReader(script)
+		if out, err := check.CombinedOutput(); err != nil {
+			t.Errorf("%s init has syntax errors: %v\n%s", shell, err, out)
+		}
+	}
+
+	script, err := shellInit("fish", "fn", "fn", rootCmd, false)
+	if err != nil {
+		t.Fatalf("shellInit(fish) failed: %v", err)
+	}
+	if strings.Contains(script, "__fn_record") {
+		t.Error("Expected no record hook when record is false")
+	}
+
+	if _, err := shellInit("powershell", "fn", "fn", rootCmd, true); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
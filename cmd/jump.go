@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// hooksMarker is the line separating the directory from the on-enter hooks
+// in the output of cd commands. Directories are never printed with a
+// newline in them, so a path cannot fake it.
+const hooksMarker = "#fn-hooks"
+
+// sessionID identifies the calling shell; the wrapper passes it so that every
+// shell gets its own back/forward stack
+var sessionID string
+
+// jumpTo prints the bookmark's directory for the shell wrapper to cd into
+// and pushes the jump onto the session's back/forward stack. Stack failures
+// only warn, so they never block navigation. Remote targets are printed as
+// is for the wrapper to open with 'fn _ssh', and are not pushed, since there
+// is no directory to go back to.
+//
+// If the wrapper evaluates hooks (it sets FN_HOOKS), the bookmark's on-enter
+// hooks follow hooksMarker, provided they have been allowed.
+func jumpTo(alias string, bookmark *storage.Bookmark) error {
+	path := bookmark.Dir()
+	if err := checkPrintable(path); err != nil {
+		return err
+	}
+	if sessionID != "" && !storage.IsRemote(path) {
+		if err := pushJump(path); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to update navigation stack: %v\n", err)
+		}
+	}
+
+	fmt.Print(path)
+	if os.Getenv("FN_HOOKS") != "" && len(bookmark.Hooks) > 0 && !storage.IsRemote(path) {
+		printHooks(alias, bookmark)
+	}
+	return nil
+}
+
+// printHooks prints the bookmark's hooks for the wrapper to evaluate, or
+// warns that they are not allowed yet
+func printHooks(alias string, bookmark *storage.Bookmark) {
+	trust, err := storage.LoadTrust()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: hooks not run: %v\n", err)
+		return
+	}
+	if !trust.Trusted(alias, bookmark) {
+		fmt.Fprintf(os.Stderr, "warning: hooks of '%s' are not allowed; review them with 'fn hook show %s', then run 'fn hook allow %s'\n", alias, alias, alias)
+		return
+	}
+	fmt.Print("\n" + hooksMarker + "\n" + strings.Join(bookmark.Hooks, "\n"))
+}
+
+// checkPrintable refuses directories the shell wrapper could not tell apart
+// from the hooks that follow them
+func checkPrintable(path string) error {
+	if strings.ContainsAny(path, "\n\r") {
+		return fmt.Errorf("refusing to navigate to a path containing a newline: %q", path)
+	}
+	return nil
+}
+
+func pushJump(path string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	session, err := storage.OpenSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return session.Push(cwd, path)
+}
+
+// openSession opens the stack of the calling shell
+func openSession() (*storage.Session, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("no shell session: set FN_SESSION or pass --session (the shell wrapper does this)")
+	}
+	return storage.OpenSession(sessionID)
+}
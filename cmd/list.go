@@ -1,43 +1,221 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
+	"github.com/rethil/fast-nav/internal/remote"
 	"github.com/rethil/fast-nav/internal/storage"
 )
 
+var (
+	listSort      string
+	listReverse   bool
+	listMissing   bool
+	listExisting  bool
+	listUnused    bool
+	listUsedSince string
+	listUnder     string
+	listLimit     int
+	listTree      bool
+	listDepth     int
+	listProbe     bool
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all saved aliases",
+	Long: `List saved bookmarks, sorted by name unless --sort says otherwise.
+
+Sort keys: name and path sort ascending; used, last-used, created and
+frecency put the most used, most recent or newest bookmarks first.
+--reverse flips any order.
+
+--tree groups bookmarks by directory, collapsing shared path prefixes;
+--depth limits how many levels of it are expanded.
+
+Remote (ssh://) bookmarks are not checked for existence unless --probe is
+given, which asks each host over ssh.`,
+	Example: `  fn list --sort frecency --limit 5
+  fn list --missing
+  fn list --used-since 7d --under ~/src
+  fn list --tree --depth 2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := listQuery()
+		if err != nil {
+			return err
+		}
+
 		store, err := storage.NewStore()
 		if err != nil {
 			return fmt.Errorf("failed to initialize storage: %w", err)
 		}
 		
-		bookmarks := store.GetAllBookmarks()
-		if len(bookmarks) == 0 {
+		matches, err := store.Query(query)
+		if err != nil {
+			return err
+		}
+
+		probed := make(map[string]error)
+		if listProbe {
+			probed = probeRemotes(matches)
+		}
+
+		if machineOutput() {
+			var records []bookmarkRecord
+			for _, match := range matches {
+				record := newBookmarkRecord(match.Alias, match.Bookmark)
+				record.Synonyms = store.SynonymsOf(match.Alias)
+				if err, ok := probed[match.Alias]; ok {
+					record.Exists = err == nil
+				}
+				records = append(records, record)
+			}
+			return writeRecords(os.Stdout, records)
+		}
+
+		if len(store.GetAllBookmarks()) == 0 {
 			fmt.Println("No bookmarks saved yet. Use 'fn save <alias>' to create one.")
 			return nil
 		}
+		if len(matches) == 0 {
+			fmt.Println("No bookmarks match the given filters.")
+			return nil
+		}
+
+		if listTree {
+			renderTree(os.Stdout, buildBookmarkTree(matches), listDepth)
+			return nil
+		}
 		
-		for alias, bookmark := range bookmarks {
-			// Check if directory still exists
-			exists := true
-			if _, err := os.Stat(bookmark.Path); os.IsNotExist(err) {
-				exists = false
+		for _, match := range matches {
+			alias, bookmark := match.Alias, match.Bookmark
+			also := alsoKnownAs(store.SynonymsOf(alias))
+
+			if storage.IsRemote(bookmark.Path) {
+				printRemoteBookmark(alias, bookmark, probed, also)
+				continue
 			}
+
+			// Check if directory still exists
+			exists := !storage.Missing(bookmark.Path)
 			
-			if exists {
-				color.Green("📍 %-12s → %s (used %d times)", alias, bookmark.Path, bookmark.UsedCount)
+			if exists && bookmark.File {
+				color.Green("📄 %-12s → %s (used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
+			} else if exists {
+				color.Green("📍 %-12s → %s (used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
 			} else {
-				color.Red("❌ %-12s → %s (MISSING - used %d times)", alias, bookmark.Path, bookmark.UsedCount)
+				color.Red("❌ %-12s → %s (MISSING - used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
 			}
 		}
 		
 		return nil
 	},
+}
+
+func printRemoteBookmark(alias string, bookmark *storage.Bookmark, probed map[string]error, also string) {
+	err, ok := probed[alias]
+	switch {
+	case !ok:
+		color.Cyan("🌐 %-12s → %s (used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
+	case err == nil:
+		color.Green("🌐 %-12s → %s (used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
+	case errors.Is(err, storage.ErrPathMissing):
+		color.Red("❌ %-12s → %s (MISSING - used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
+	default:
+		color.Yellow("⚠️  %-12s → %s (UNREACHABLE - used %d times)%s", alias, bookmark.Path, bookmark.UsedCount, also)
+	}
+}
+
+// alsoKnownAs renders a bookmark's synonyms for the end of its list line
+func alsoKnownAs(synonyms []string) string {
+	if len(synonyms) == 0 {
+		return ""
+	}
+	return " (also " + strings.Join(synonyms, ", ") + ")"
+}
+
+// probeRemotes checks the remote bookmarks among matches over ssh, keyed
+// by alias
+func probeRemotes(matches []storage.FuzzyMatch) map[string]error {
+	probed := make(map[string]error)
+	for _, match := range matches {
+		if !storage.IsRemote(match.Bookmark.Path) {
+			continue
+		}
+		target, err := remote.Parse(match.Bookmark.Path)
+		if err == nil {
+			err = target.Probe()
+		}
+		probed[match.Alias] = err
+	}
+	return probed
+}
+
+// listQuery builds the storage query from list's flags
+func listQuery() (storage.Query, error) {
+	if listMissing && listExisting {
+		return storage.Query{}, fmt.Errorf("--missing and --existing cannot be combined")
+	}
+	if listTree && machineOutput() {
+		return storage.Query{}, fmt.Errorf("--tree cannot be combined with --output")
+	}
+	if listDepth < 0 {
+		return storage.Query{}, fmt.Errorf("--depth must not be negative")
+	}
+
+	query := storage.Query{
+		Sort:     listSort,
+		Reverse:  listReverse,
+		Missing:  listMissing,
+		Existing: listExisting,
+		Unused:   listUnused,
+		Limit:    listLimit,
+	}
+
+	if listUsedSince != "" {
+		age, err := storage.ParseAge(listUsedSince)
+		if err != nil {
+			return storage.Query{}, err
+		}
+		query.UsedSince = time.Now().Add(-age)
+	}
+
+	if listUnder != "" {
+		dir, err := homedir.Expand(listUnder)
+		if err != nil {
+			return storage.Query{}, fmt.Errorf("failed to expand path: %w", err)
+		}
+		query.Under, err = filepath.Abs(dir)
+		if err != nil {
+			return storage.Query{}, fmt.Errorf("failed to resolve path: %w", err)
+		}
+	}
+
+	return query, nil
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listSort, "sort", "name", "sort by "+strings.Join(storage.SortKeys, "|"))
+	listCmd.Flags().BoolVar(&listReverse, "reverse", false, "reverse the sort order")
+	listCmd.Flags().BoolVar(&listMissing, "missing", false, "only bookmarks whose directory no longer exists")
+	listCmd.Flags().BoolVar(&listExisting, "existing", false, "only bookmarks whose directory exists")
+	listCmd.Flags().BoolVar(&listUnused, "unused", false, "only bookmarks that were never used")
+	listCmd.Flags().StringVar(&listUsedSince, "used-since", "", "only bookmarks used within this long, e.g. 12h, 7d, 2w")
+	listCmd.Flags().StringVar(&listUnder, "under", "", "only bookmarks at or below this directory")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "show at most this many bookmarks")
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "show bookmarks as a directory tree")
+	listCmd.Flags().IntVar(&listDepth, "depth", 0, "with --tree, expand at most this many levels (0 = all)")
+	listCmd.Flags().BoolVar(&listProbe, "probe", false, "check over ssh whether remote bookmarks still exist")
+
+	listCmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return storage.SortKeys, cobra.ShellCompDirectiveNoFileComp
+	})
 }
\ No newline at end of file
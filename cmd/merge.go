@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/merge"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	mergeBase     string
+	mergeStrategy string
+	mergeDryRun   bool
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <other.json>",
+	Short: "Merge another copy of bookmarks.json into this one",
+	Long: `Merge bookmarks from another copy of bookmarks.json (or a JSON file
+written by 'fn export') into your bookmarks, alias by alias.
+
+With --base, the copy both sides started from, the merge is three-way:
+aliases added, re-pointed or deleted on only one side keep that change.
+Without it, bookmarks from both sides are kept and nothing is deleted.
+
+For aliases both sides keep at the same path, usage counts are summed, and
+the latest use and earliest creation time are kept. An alias pointing at
+different paths is a conflict, resolved by --strategy or, in a terminal,
+by asking:
+  ours    keep this machine's bookmark
+  theirs  take the bookmark from <other.json>
+  rename  keep both, saving theirs under a new alias such as proj-2`,
+	Example: `  fn merge laptop-bookmarks.json --dry-run
+  fn merge other.json --base last-common.json --strategy rename`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mergeStrategy != "" && mergeStrategy != "ours" && mergeStrategy != "theirs" && mergeStrategy != "rename" {
+			return fmt.Errorf("invalid --strategy value: %s (want ours, theirs or rename)", mergeStrategy)
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		theirs, err := storage.ReadBookmarksFile(args[0])
+		if err != nil {
+			return err
+		}
+		base := &storage.BookmarkData{}
+		if mergeBase != "" {
+			base, err = storage.ReadBookmarksFile(mergeBase)
+			if err != nil {
+				return err
+			}
+		}
+
+		ours := store.GetAllBookmarks()
+		result := merge.ThreeWay(base.Bookmarks, ours, theirs.Bookmarks)
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+		if err := resolveConflicts(result, mergeStrategy, interactive); err != nil {
+			return err
+		}
+		synonyms := merge.Synonyms(base.Synonyms, store.GetSynonyms(), theirs.Synonyms, result.Bookmarks)
+
+		printMergePlan(os.Stdout, ours, result.Bookmarks, mergeDryRun)
+		if mergeDryRun {
+			return nil
+		}
+
+		return store.Batch(func() error {
+			// Synonyms go first, so that none of them blocks a merged alias
+			if err := store.SetSynonyms(nil); err != nil {
+				return err
+			}
+			for alias := range ours {
+				if result.Bookmarks[alias] == nil {
+					if err := store.DeleteBookmark(alias); err != nil {
+						return fmt.Errorf("failed to delete bookmark '%s': %w", alias, err)
+					}
+				}
+			}
+			for alias, bookmark := range result.Bookmarks {
+				err := store.PutBookmark(alias, bookmark)
+				if err != nil {
+					return fmt.Errorf("failed to save bookmark '%s': %w", alias, err)
+				}
+			}
+			if err := store.SetSynonyms(synonyms); err != nil {
+				return fmt.Errorf("failed to save synonyms: %w", err)
+			}
+			return nil
+		})
+	},
+}
+
+// resolveConflicts settles every conflict into result.Bookmarks, using
+// strategy, or asking the user when it is empty and interactive is set
+func resolveConflicts(result *merge.Result, strategy string, interactive bool) error {
+	if len(result.Conflicts) == 0 {
+		return nil
+	}
+
+	if strategy == "" && !interactive {
+		aliases := make([]string, len(result.Conflicts))
+		for i, conflict := range result.Conflicts {
+			aliases[i] = conflict.Alias
+		}
+		return fmt.Errorf("%d conflicting alias(es): %s; use --strategy ours, theirs or rename",
+			len(aliases), strings.Join(aliases, ", "))
+	}
+
+	taken := func(alias string) bool {
+		if result.Bookmarks[alias] != nil {
+			return true
+		}
+		for _, conflict := range result.Conflicts {
+			if conflict.Alias == alias {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, conflict := range result.Conflicts {
+		choice := strategy
+		if choice == "" {
+			options := []string{
+				"ours: " + conflict.Ours.Path,
+				"theirs: " + conflict.Theirs.Path,
+				"rename: keep both",
+			}
+			answer := ""
+			prompt := &survey.Select{
+				Message: fmt.Sprintf("'%s' points to different directories:", conflict.Alias),
+				Options: options,
+			}
+			if err := survey.AskOne(prompt, &answer); err != nil {
+				return err
+			}
+			choice, _, _ = strings.Cut(answer, ":")
+		}
+
+		switch choice {
+		case "ours":
+			result.Bookmarks[conflict.Alias] = conflict.Ours
+		case "theirs":
+			result.Bookmarks[conflict.Alias] = conflict.Theirs
+		case "rename":
+			result.Bookmarks[conflict.Alias] = conflict.Ours
+			alias := numberedAlias(conflict.Alias, taken)
+			if alias == "" {
+				return fmt.Errorf("no free alias to rename '%s' to", conflict.Alias)
+			}
+			result.Bookmarks[alias] = conflict.Theirs
+		}
+	}
+	return nil
+}
+
+func printMergePlan(w io.Writer, before, after map[string]*storage.Bookmark, dryRun bool) {
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	red := color.New(color.FgRed)
+
+	var aliases []string
+	for alias := range before {
+		aliases = append(aliases, alias)
+	}
+	for alias := range after {
+		if before[alias] == nil {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+
+	var added, updated, removed int
+	for _, alias := range aliases {
+		old, merged := before[alias], after[alias]
+		switch {
+		case old == nil:
+			added++
+			green.Fprintf(w, "+ %-15s", alias)
+			fmt.Fprintf(w, " → %s\n", merged.Path)
+		case merged == nil:
+			removed++
+			red.Fprintf(w, "- %-15s", alias)
+			fmt.Fprintf(w, " → %s\n", old.Path)
+		case !merge.Equal(old, merged):
+			updated++
+			yellow.Fprintf(w, "~ %-15s", alias)
+			fmt.Fprintf(w, " → %s\n", merged.Path)
+		}
+	}
+
+	verb := "Merged"
+	if dryRun {
+		verb = "Would merge"
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s: %d added, %d updated, %d removed\n", verb, added, updated, removed)
+	if dryRun {
+		color.New(color.FgHiBlack).Fprintln(w, "Dry run: nothing was saved.")
+	}
+}
+
+func init() {
+	mergeCmd.Flags().StringVar(&mergeBase, "base", "", "the common ancestor of both copies, for a three-way merge")
+	mergeCmd.Flags().StringVar(&mergeStrategy, "strategy", "", "resolve conflicts with ours, theirs or rename instead of asking")
+	mergeCmd.Flags().BoolVar(&mergeDryRun, "dry-run", false, "show what would change without saving")
+
+	mergeCmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"ours", "theirs", "rename"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/merge"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	newResult := func() *merge.Result {
+		return &merge.Result{
+			Bookmarks: map[string]*storage.Bookmark{"proj-2": {Path: "/taken"}},
+			Conflicts: []merge.Conflict{{
+				Alias:  "proj",
+				Ours:   &storage.Bookmark{Path: "/ours"},
+				Theirs: &storage.Bookmark{Path: "/theirs"},
+			}},
+		}
+	}
+
+	tests := map[string]map[string]string{
+		"ours":   {"proj": "/ours", "proj-2": "/taken"},
+		"theirs": {"proj": "/theirs", "proj-2": "/taken"},
+		"rename": {"proj": "/ours", "proj-2": "/taken", "proj-3": "/theirs"},
+	}
+	for strategy, want := range tests {
+		result := newResult()
+		if err := resolveConflicts(result, strategy, false); err != nil {
+			t.Fatalf("%s: resolveConflicts failed: %v", strategy, err)
+		}
+		if len(result.Bookmarks) != len(want) {
+			t.Errorf("%s: expected %d bookmarks, got %v", strategy, len(want), result.Bookmarks)
+		}
+		for alias, path := range want {
+			if result.Bookmarks[alias] == nil || result.Bookmarks[alias].Path != path {
+				t.Errorf("%s: expected %s → %s, got %+v", strategy, alias, path, result.Bookmarks[alias])
+			}
+		}
+	}
+
+	// Without a strategy or a terminal to ask on, conflicts are an error
+	if err := resolveConflicts(newResult(), "", false); err == nil {
+		t.Error("Expected an error for unresolved conflicts")
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -13,87 +14,79 @@ var navigateCmd = &cobra.Command{
 	Use:               "navigate <alias>",
 	Aliases:           []string{"<alias>"},
 	Short:             "Output path for navigation (used by shell function)",
+	Annotations:       map[string]string{cdAnnotation: "true"},
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: aliasCompletionFunc,
+	ValidArgsFunction: nameCompletionFunc,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		alias := args[0]
-
 		store, err := storage.NewStore()
 		if err != nil {
 			return fmt.Errorf("failed to initialize storage: %w", err)
 		}
 
-		// Try exact match first
-		bookmark, exists := store.GetBookmark(alias)
-		if exists {
-			// Check if directory still exists
-			if _, err := os.Stat(bookmark.Path); os.IsNotExist(err) {
-				return fmt.Errorf("directory no longer exists: %s", bookmark.Path)
-			}
-
-			// Update usage stats
-			store.UpdateUsage(alias)
-
-			// Output the path for shell to use
-			fmt.Print(bookmark.Path)
-			return nil
+		pattern := args[0]
+		alias, bookmark, err := resolveBookmark(store, pattern)
+		if err != nil {
+			return err
 		}
 
-		// Try fuzzy matching
-		matches := store.FindFuzzyMatches(alias)
-		if len(matches) == 0 {
-			// Try smart suggestions for typos
-			suggestions := store.GetSuggestions(alias, 3) // Allow up to 3 character edits
-			if len(suggestions) > 0 {
-				yellow := color.New(color.FgYellow)
-				cyan := color.New(color.FgCyan)
-
-				fmt.Fprintf(os.Stderr, "No exact match found for '%s'. Did you mean:\n\n", alias)
-				for i, suggestion := range suggestions {
-					if i >= 5 { // Limit to top 5 suggestions
-						break
-					}
-					yellow.Fprintf(os.Stderr, "  %s", suggestion.Alias)
-					fmt.Fprintf(os.Stderr, " -> ")
-					cyan.Fprintf(os.Stderr, "%s\n", suggestion.Bookmark.Path)
-				}
-				fmt.Fprintf(os.Stderr, "\n")
-			}
-			return fmt.Errorf("no bookmarks found matching '%s'", alias)
+		// Check if directory still exists
+		err = storage.CheckDir(bookmark.Path)
+		if err != nil {
+			return err
 		}
 
-		// If we have exactly one match, use it
-		if len(matches) == 1 {
-			match := matches[0]
-
-			// Check if directory still exists
-			if _, err := os.Stat(match.Bookmark.Path); os.IsNotExist(err) {
-				return fmt.Errorf("directory no longer exists: %s", match.Bookmark.Path)
-			}
-
-			// Update usage stats
-			store.UpdateUsage(match.Alias)
+		// Update usage stats
+		store.UpdateUsageWith(alias, resolutionFor(store, alias, pattern))
 
-			// Output the path for shell to use
-			fmt.Print(match.Bookmark.Path)
-			return nil
-		}
+		// Output the path for shell to use
+		return jumpTo(alias, bookmark)
+	},
+}
 
-		// Multiple matches - show them to the user
-		yellow := color.New(color.FgYellow)
-		cyan := color.New(color.FgCyan)
+// resolutionFor reports how pattern resolved to alias; naming the bookmark by
+// one of its synonyms counts as exact
+func resolutionFor(store *storage.Store, alias, pattern string) storage.Resolution {
+	if found, _, exists := store.Lookup(pattern); exists && found == alias {
+		return storage.ResolutionExact
+	}
+	return storage.ResolutionFuzzy
+}
 
-		fmt.Fprintf(os.Stderr, "Multiple matches found for '%s':\n\n", alias)
+// resolveBookmark resolves an alias or fuzzy pattern, printing typo
+// suggestions or the competing matches to stderr when it fails
+func resolveBookmark(store *storage.Store, pattern string) (string, *storage.Bookmark, error) {
+	alias, bookmark, err := store.Resolve(pattern)
+	if err == nil {
+		return alias, bookmark, nil
+	}
+
+	yellow := color.New(color.FgYellow)
+	cyan := color.New(color.FgCyan)
+	printMatches := func(matches []storage.FuzzyMatch) {
 		for i, match := range matches {
-			if i >= 5 { // Limit to top 5 matches
+			if i >= 5 { // Limit to top 5
 				break
 			}
 			yellow.Fprintf(os.Stderr, "  %s", match.Alias)
 			fmt.Fprintf(os.Stderr, " -> ")
 			cyan.Fprintf(os.Stderr, "%s\n", match.Bookmark.Path)
 		}
+	}
+
+	var ambiguous *storage.AmbiguousError
+	if errors.As(err, &ambiguous) {
+		fmt.Fprintf(os.Stderr, "Multiple matches found for '%s':\n\n", pattern)
+		printMatches(ambiguous.Matches)
 		fmt.Fprintf(os.Stderr, "\nPlease use a more specific alias.\n")
+	} else if errors.Is(err, storage.ErrNotFound) {
+		// Try smart suggestions for typos
+		suggestions := store.GetSuggestions(pattern, 3) // Allow up to 3 character edits
+		if len(suggestions) > 0 {
+			fmt.Fprintf(os.Stderr, "No exact match found for '%s'. Did you mean:\n\n", pattern)
+			printMatches(suggestions)
+			fmt.Fprintf(os.Stderr, "\n")
+		}
+	}
 
-		return fmt.Errorf("ambiguous match")
-	},
+	return "", nil, err
 }
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	openWith     string
+	openRemember bool
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <alias> [--with editor|files|code]",
+	Short: "Open a bookmark in your editor, file manager or IDE",
+	Long: `Open a bookmarked file or directory with an opener. The alias is resolved
+like 'fn <alias>', including fuzzy matches.
+
+Built-in openers:
+
+  editor  $VISUAL, or $EDITOR if that is not set (falling back to vi)
+  files   the desktop's file manager or default application (xdg-open, or
+          open on macOS)
+  code    Visual Studio Code
+
+The opener is taken from --with, then from the bookmark (set it with
+--remember), then from "default_opener" in ~/.fn/config.json, and is
+"editor" otherwise. Openers can be added or replaced in the config file;
+each is a shell command that is run with the path appended:
+
+  {
+    "default_opener": "files",
+    "openers": {
+      "code": "code --new-window",
+      "idea": "idea"
+    }
+  }`,
+	Example: `  fn save runbook ~/ops/runbook.md
+  fn open runbook
+  fn open api --with code --remember`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: nameCompletionFunc,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if openRemember && openWith == "" {
+			return fmt.Errorf("--remember needs --with")
+		}
+
+		config, err := storage.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		pattern := args[0]
+		alias, bookmark, err := resolveBookmark(store, pattern)
+		if err != nil {
+			return err
+		}
+		if storage.IsRemote(bookmark.Path) {
+			return fmt.Errorf("cannot open remote bookmark '%s' locally; use 'fn %s' to ssh there", alias, alias)
+		}
+		err = storage.CheckDir(bookmark.Path)
+		if err != nil {
+			return err
+		}
+
+		name := openWith
+		if name == "" {
+			name = bookmark.Opener
+		}
+		if name == "" {
+			name = config.DefaultOpener
+		}
+		opener, err := config.Opener(name)
+		if err != nil {
+			return err
+		}
+
+		if openRemember {
+			err = store.SetOpener(alias, name)
+			if err != nil {
+				return fmt.Errorf("failed to save opener: %w", err)
+			}
+		}
+
+		store.UpdateUsageWith(alias, resolutionFor(store, alias, pattern))
+
+		return runOpener(opener, bookmark.Path)
+	},
+}
+
+// runOpener runs an opener command on path, attached to the terminal. The
+// command may hold arguments (e.g. "code --wait"), so it is run through sh,
+// as git does with $EDITOR.
+func runOpener(opener, path string) error {
+	cmd := exec.Command("sh", "-c", opener+` "$@"`, opener, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run opener '%s': %w", opener, err)
+	}
+	return nil
+}
+
+func init() {
+	openCmd.Flags().StringVar(&openWith, "with", "", "opener to use, e.g. editor, files or code")
+	openCmd.Flags().BoolVar(&openRemember, "remember", false, "use this opener for the bookmark from now on")
+
+	openCmd.RegisterFlagCompletionFunc("with", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		config, err := storage.LoadConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return config.OpenerNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestOpenCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	runbook := filepath.Join(tempDir, "runbook.md")
+	if err := os.WriteFile(runbook, []byte("# Runbook\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	store, err := storage.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if err := store.SaveFileBookmark("runbook", runbook); err != nil {
+		t.Fatalf("Failed to save bookmark: %v", err)
+	}
+	store.SaveBookmark("build", "ssh://build1/srv")
+
+	// The editor records what it was asked to open
+	opened := filepath.Join(tempDir, "opened")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `printf '%s' >`+opened)
+
+	if err := openCmd.RunE(openCmd, []string{"runb"}); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	got, _ := os.ReadFile(opened)
+	if string(got) != runbook {
+		t.Errorf("Expected the editor to open %s, got %q", runbook, got)
+	}
+
+	store, _ = storage.NewStore()
+	if bookmark, _ := store.GetBookmark("runbook"); bookmark.UsedCount != 1 {
+		t.Errorf("Expected usage to be recorded, got %d", bookmark.UsedCount)
+	}
+
+	if err := openCmd.RunE(openCmd, []string{"build"}); err == nil {
+		t.Error("Expected an error opening a remote bookmark")
+	}
+}
+
+func TestOpenWith(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	defer func() { openWith, openRemember = "", false }()
+
+	// A stub opener records its name and what it was asked to open
+	opened := filepath.Join(tempDir, "opened")
+	stub := filepath.Join(tempDir, "stub")
+	os.WriteFile(stub, []byte("#!/bin/sh\necho \"$0 $*\" >> "+opened+"\n"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, ".fn"), 0755)
+	config := `{"default_opener": "files", "openers": {"files": "` + stub + ` files", "code": "` + stub + ` code"}}`
+	os.WriteFile(filepath.Join(tempDir, ".fn", "config.json"), []byte(config), 0644)
+
+	project := filepath.Join(tempDir, "project")
+	os.Mkdir(project, 0755)
+	store, _ := storage.NewStore()
+	store.SaveBookmark("project", project)
+
+	open := func(with string, remember bool) {
+		t.Helper()
+		openWith, openRemember = with, remember
+		if err := openCmd.RunE(openCmd, []string{"project"}); err != nil {
+			t.Fatalf("open --with %q failed: %v", with, err)
+		}
+	}
+	open("", false)
+	open("code", true)
+	open("", false)
+
+	got, _ := os.ReadFile(opened)
+	want := stub + " files " + project + "\n" + stub + " code " + project + "\n" + stub + " code " + project + "\n"
+	if string(got) != want {
+		t.Errorf("Expected openers to run as\n%s\ngot\n%s", want, got)
+	}
+
+	store, _ = storage.NewStore()
+	if bookmark, _ := store.GetBookmark("project"); bookmark.Opener != "code" {
+		t.Errorf("Expected --remember to store the opener, got %q", bookmark.Opener)
+	}
+
+	openWith = "nope"
+	if err := openCmd.RunE(openCmd, []string{"project"}); err == nil {
+		t.Error("Expected an error for an unknown opener")
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// outputFormat is the value of the global --output flag; empty means the
+// human-readable default
+var outputFormat string
+
+const outputHelp = `output format: text, json, jsonl, tsv, nul (alias<TAB>path records), or template=<go template> (list, search, recent, cleanup; stats: json)`
+
+// outputAnnotation marks the commands that honour --output
+const outputAnnotation = "fn.output"
+
+// honourOutput marks cmd as printing in the format chosen by --output
+func honourOutput(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[outputAnnotation] = "true"
+}
+
+// checkOutputSupported refuses a machine-readable --output on a command that
+// would otherwise ignore it
+func checkOutputSupported(cmd *cobra.Command) error {
+	if machineOutput() && cmd.Annotations[outputAnnotation] != "true" {
+		return fmt.Errorf("fn %s does not support --output", cmd.Name())
+	}
+	return nil
+}
+
+// bookmarkRecord is the stable machine-readable form of a bookmark. Fields
+// may be added but are never renamed or removed.
+type bookmarkRecord struct {
+	Alias     string    `json:"alias"`
+	Path      string    `json:"path"`
+	Exists    bool      `json:"exists"`
+	UsedCount int       `json:"used_count"`
+	Created   time.Time `json:"created"`
+	LastUsed  time.Time `json:"last_used"`
+	Tags      []string  `json:"tags"`
+	Synonyms  []string  `json:"synonyms,omitempty"`
+}
+
+func newBookmarkRecord(alias string, bookmark *storage.Bookmark) bookmarkRecord {
+	tags := bookmark.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return bookmarkRecord{
+		Alias:     alias,
+		Path:      bookmark.Path,
+		Exists:    !storage.Missing(bookmark.Path),
+		UsedCount: bookmark.UsedCount,
+		Created:   bookmark.Created,
+		LastUsed:  bookmark.LastUsed,
+		Tags:      tags,
+	}
+}
+
+// machineOutput reports whether --output asked for something other than text
+func machineOutput() bool {
+	return outputFormat != "" && outputFormat != "text"
+}
+
+// validateOutputFormat rejects unknown --output values before a command runs
+func validateOutputFormat() error {
+	switch outputFormat {
+	case "", "text", "json", "jsonl", "tsv", "nul":
+		return nil
+	}
+	if text, ok := strings.CutPrefix(outputFormat, "template="); ok {
+		_, err := parseOutputTemplate(text)
+		return err
+	}
+	return fmt.Errorf("unknown output format: %s (want text, json, jsonl, tsv, nul or template=...)", outputFormat)
+}
+
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeRecords prints records in the format selected by --output
+func writeRecords(w io.Writer, records []bookmarkRecord) error {
+	if records == nil {
+		records = []bookmarkRecord{}
+	}
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "tsv":
+		for _, record := range records {
+			lastUsed := ""
+			if !record.LastUsed.IsZero() {
+				lastUsed = record.LastUsed.Format(time.RFC3339)
+			}
+			fields := []string{
+				record.Alias,
+				record.Path,
+				strconv.FormatBool(record.Exists),
+				strconv.Itoa(record.UsedCount),
+				lastUsed,
+				strings.Join(record.Tags, ","),
+			}
+			for i, field := range fields {
+				fields[i] = escapeTSV(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "nul":
+		// Aliases never contain a tab, so the first one splits the record
+		// even when the path has more
+		for _, record := range records {
+			if _, err := fmt.Fprintf(w, "%s\t%s\x00", record.Alias, record.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	text, ok := strings.CutPrefix(outputFormat, "template=")
+	if !ok {
+		return validateOutputFormat()
+	}
+	tmpl, err := parseOutputTemplate(text)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := tmpl.Execute(w, record); err != nil {
+			return fmt.Errorf("failed to render output template: %w", err)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// escapeTSV keeps a field on one line and within its column
+func escapeTSV(field string) string {
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(field)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWriteRecords(t *testing.T) {
+	defer func() { outputFormat = "" }()
+
+	records := []bookmarkRecord{
+		{Alias: "proj", Path: "/tmp/my\tproj", Exists: true, UsedCount: 3, LastUsed: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Tags: []string{"go", "work"}},
+		{Alias: "gone", Path: "/tmp/gone", Tags: []string{}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"tsv", "proj\t/tmp/my\\tproj\ttrue\t3\t2024-01-02T03:04:05Z\tgo,work\ngone\t/tmp/gone\tfalse\t0\t\t\n"},
+		{"nul", "proj\t/tmp/my\tproj\x00gone\t/tmp/gone\x00"},
+		{"template={{.Alias}}:{{join .Tags \"+\"}}", "proj:go+work\ngone:\n"},
+	}
+	for _, tt := range tests {
+		outputFormat = tt.format
+		var out bytes.Buffer
+		if err := writeRecords(&out, records); err != nil {
+			t.Fatalf("%s: writeRecords failed: %v", tt.format, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.format, tt.want, out.String())
+		}
+	}
+
+	outputFormat = "json"
+	var out bytes.Buffer
+	if err := writeRecords(&out, nil); err != nil {
+		t.Fatalf("writeRecords failed: %v", err)
+	}
+	var decoded []bookmarkRecord
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded == nil {
+		t.Errorf("Expected an empty JSON array for no records, got %q", out.String())
+	}
+
+	for _, format := range []string{"xml", "template={{.Alias"} {
+		outputFormat = format
+		if err := validateOutputFormat(); err == nil {
+			t.Errorf("Expected %q to be rejected", format)
+		}
+	}
+}
+
+func TestOutputFlagScope(t *testing.T) {
+	defer func() { outputFormat = "" }()
+
+	outputFormat = "json"
+	for _, cmd := range []*cobra.Command{listCmd, searchCmd, recentCmd, cleanupCmd, statsCmd} {
+		if cmd.InheritedFlags().Lookup("output") == nil {
+			t.Errorf("Expected %s to inherit the global --output", cmd.Name())
+		}
+		if err := rootCmd.PersistentPreRunE(cmd, nil); err != nil {
+			t.Errorf("Expected %s to honour --output, got %v", cmd.Name(), err)
+		}
+	}
+	for _, cmd := range []*cobra.Command{saveCmd, exportCmd, navigateCmd} {
+		if err := rootCmd.PersistentPreRunE(cmd, nil); err == nil {
+			t.Errorf("Expected %s to reject --output rather than ignore it", cmd.Name())
+		}
+	}
+
+	outputFormat = "text"
+	if err := rootCmd.PersistentPreRunE(saveCmd, nil); err != nil {
+		t.Errorf("Expected --output text to be accepted everywhere, got %v", err)
+	}
+}
@@ -11,7 +11,7 @@ var pathCmd = &cobra.Command{
 	Use:               "path <alias>",
 	Short:             "Print path without navigating",
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: aliasCompletionFunc,
+	ValidArgsFunction: nameCompletionFunc,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		alias := args[0]
 		
@@ -20,9 +20,9 @@ var pathCmd = &cobra.Command{
 			return fmt.Errorf("failed to initialize storage: %w", err)
 		}
 		
-		bookmark, exists := store.GetBookmark(alias)
+		_, bookmark, exists := store.Lookup(alias)
 		if !exists {
-			return fmt.Errorf("alias '%s' not found", alias)
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, alias)
 		}
 		
 		fmt.Println(bookmark.Path)
@@ -15,6 +15,7 @@ var recentCmd = &cobra.Command{
 	Use:     "recent [index]",
 	Aliases: []string{"r"},
 	Short:   "Navigate to recently used bookmarks",
+	Annotations: map[string]string{cdAnnotation: "true"},
 	Long: `Navigate to recently used bookmarks. 
 If no index is provided, shows a list of recent bookmarks.
 If an index is provided (1-9), navigates to that bookmark directly.`,
@@ -27,7 +28,7 @@ If an index is provided (1-9), navigates to that bookmark directly.`,
 		
 		recentBookmarks := store.GetRecentlyUsed(9) // Limit to 9 for single-digit selection
 		
-		if len(recentBookmarks) == 0 {
+		if len(recentBookmarks) == 0 && !machineOutput() {
 			fmt.Println("No bookmarks found")
 			return nil
 		}
@@ -46,18 +47,25 @@ If an index is provided (1-9), navigates to that bookmark directly.`,
 			bookmark := recentBookmarks[index-1]
 			
 			// Check if directory still exists
-			if _, err := os.Stat(bookmark.Bookmark.Path); os.IsNotExist(err) {
-				return fmt.Errorf("directory no longer exists: %s", bookmark.Bookmark.Path)
+			if err := storage.CheckDir(bookmark.Bookmark.Path); err != nil {
+				return err
 			}
 			
 			// Update usage stats
-			store.UpdateUsage(bookmark.Alias)
+			store.UpdateUsageWith(bookmark.Alias, storage.ResolutionRecent)
 			
 			// Output the path for shell to use
-			fmt.Print(bookmark.Bookmark.Path)
-			return nil
+			return jumpTo(bookmark.Alias, bookmark.Bookmark)
 		}
 		
+		if machineOutput() {
+			var records []bookmarkRecord
+			for _, bookmark := range recentBookmarks {
+				records = append(records, newBookmarkRecord(bookmark.Alias, bookmark.Bookmark))
+			}
+			return writeRecords(os.Stdout, records)
+		}
+
 		// Show list of recent bookmarks
 		green := color.New(color.FgGreen)
 		yellow := color.New(color.FgYellow)
@@ -100,8 +108,4 @@ If an index is provided (1-9), navigates to that bookmark directly.`,
 		
 		return nil
 	},
-}
-
-func init() {
-	rootCmd.AddCommand(recentCmd)
 }
\ No newline at end of file
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// navigationGrace is how long after a recorded jump a cd into the same
+// bookmark is treated as the tail of that jump rather than a new use, so
+// 'fn <alias>' followed by the hook firing does not count twice.
+const navigationGrace = 5 * time.Second
+
+var recordCmd = &cobra.Command{
+	Use:    "_record <dir>",
+	Short:  "Record a directory visit (used by the shell hook)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid directory: %w", err)
+		}
+
+		history, err := storage.NewHistory()
+		if err != nil {
+			return fmt.Errorf("failed to initialize history: %w", err)
+		}
+
+		err = history.RecordVisit(dir)
+		if err != nil {
+			return fmt.Errorf("failed to record visit: %w", err)
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		// Reaching a bookmarked directory by plain cd still counts as using it
+		for _, alias := range store.FindByPath(dir) {
+			bookmark, _ := store.GetBookmark(alias)
+			if time.Since(bookmark.LastUsed) < navigationGrace {
+				continue
+			}
+			err = store.UpdateUsageWith(alias, storage.ResolutionCd)
+			if err != nil {
+				return fmt.Errorf("failed to update usage: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
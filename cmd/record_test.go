@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestRecordCommand(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fn-record-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, err := storage.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	err = store.SaveBookmark("proj", tempDir)
+	if err != nil {
+		t.Fatalf("Failed to save bookmark: %v", err)
+	}
+
+	t.Run("RecentJumpIsNotCountedTwice", func(t *testing.T) {
+		err := recordCmd.RunE(recordCmd, []string{tempDir})
+		if err != nil {
+			t.Fatalf("_record failed: %v", err)
+		}
+
+		store, _ := storage.NewStore()
+		bookmark, _ := store.GetBookmark("proj")
+		if bookmark.UsedCount != 0 {
+			t.Errorf("Expected visit within grace period to be ignored, got UsedCount %d", bookmark.UsedCount)
+		}
+	})
+
+	t.Run("PlainCdCountsAsUsage", func(t *testing.T) {
+		store, _ := storage.NewStore()
+		bookmark, _ := store.GetBookmark("proj")
+		bookmark.LastUsed = time.Now().Add(-time.Hour)
+		store.Batch(func() error { return nil }) // writes the backdated LastUsed
+
+		err := recordCmd.RunE(recordCmd, []string{tempDir})
+		if err != nil {
+			t.Fatalf("_record failed: %v", err)
+		}
+
+		store, _ = storage.NewStore()
+		bookmark, _ = store.GetBookmark("proj")
+		if bookmark.UsedCount != 1 {
+			t.Errorf("Expected UsedCount 1, got %d", bookmark.UsedCount)
+		}
+
+		history, _ := storage.NewHistory()
+		if visit := history.GetVisits()[tempDir]; visit == nil || visit.Count != 2 {
+			t.Errorf("Expected 2 recorded visits, got %+v", visit)
+		}
+	})
+}
+
+func TestRecordHookSyntax(t *testing.T) {
+	hook, err := recordHook("bash", "fn")
+	if err != nil {
+		t.Fatalf("recordHook failed: %v", err)
+	}
+
+	check := exec.Command("bash", "-n")
+	check.Stdin = strings.NewReader(hook)
+	if out, err := check.CombinedOutput(); err != nil {
+		t.Errorf("bash hook has syntax errors: %v\n%s", err, out)
+	}
+
+	if _, err := recordHook("powershell", "fn"); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
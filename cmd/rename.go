@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var renameForce bool
+
+var renameCmd = &cobra.Command{
+	Use:     "rename <old> <new>",
+	Aliases: []string{"mv"},
+	Short:   "Rename an alias, keeping its history and stats",
+	Long: `Give a bookmark a new alias. Its creation time, usage count, usage
+history, tags, hooks and tasks move with it, and so does permission for its
+hooks, variables and tasks.
+
+If the new alias is taken, rename refuses unless --force is given, in which
+case the bookmark holding it is replaced.`,
+	Example: `  fn rename proj api
+  fn rename api-old api --force`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: leadingAliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldAlias, newAlias := args[0], args[1]
+		if !isValidAlias(newAlias) {
+			return fmt.Errorf("invalid alias: use only alphanumeric characters, dash, and underscore")
+		}
+		if oldAlias == newAlias {
+			return fmt.Errorf("'%s' already has that name", oldAlias)
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+		if _, exists := store.GetBookmark(oldAlias); !exists {
+			return fmt.Errorf("%w: %s", storage.ErrNotFound, oldAlias)
+		}
+		if primary, taken := store.GetSynonyms()[newAlias]; taken {
+			return fmt.Errorf("'%s' is a synonym of '%s'; remove it with 'fn alias rm %s' first", newAlias, primary, newAlias)
+		}
+		if _, taken := store.GetBookmark(newAlias); taken && !renameForce {
+			return fmt.Errorf("'%s' already exists; use --force to replace it", newAlias)
+		}
+
+		err = renameBookmark(store, oldAlias, newAlias, renameForce)
+		if err != nil {
+			return err
+		}
+		color.Green("✓ Renamed '%s' → '%s'", oldAlias, newAlias)
+		return nil
+	},
+}
+
+// renameBookmark moves a bookmark to a new alias along with everything kept
+// under its name elsewhere. With force, a bookmark already holding newAlias
+// is replaced.
+func renameBookmark(store *storage.Store, oldAlias, newAlias string, force bool) error {
+	// Both changes are written together
+	err := store.Batch(func() error {
+		if force {
+			if err := store.DeleteBookmark(newAlias); err != nil {
+				return err
+			}
+		}
+		return store.RenameBookmark(oldAlias, newAlias)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename bookmark: %w", err)
+	}
+	return moveTrust(oldAlias, newAlias)
+}
+
+// moveTrust moves the allowed hooks of a renamed bookmark to its new alias
+func moveTrust(oldAlias, newAlias string) error {
+	trust, err := storage.LoadTrust()
+	if err == nil {
+		err = trust.Rename(oldAlias, newAlias)
+	}
+	if err != nil {
+		return fmt.Errorf("renamed '%s', but failed to move its allowed hooks: %w", oldAlias, err)
+	}
+	return nil
+}
+
+func init() {
+	renameCmd.Flags().BoolVarP(&renameForce, "force", "f", false, "replace the bookmark already using the new alias")
+}
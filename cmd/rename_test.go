@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestRenameCommand(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	defer func() { renameForce = false }()
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("proj", tempDir)
+	store.SaveBookmark("api", "/srv/api")
+	store.UpdateUsage("proj")
+	store.UpdateUsage("api")
+	if err := updateHooks("proj", []string{"nvm use"}); err != nil {
+		t.Fatalf("Failed to set hooks: %v", err)
+	}
+	created := store.GetAllBookmarks()["proj"].Created
+
+	if err := renameCmd.RunE(renameCmd, []string{"proj", "bad alias"}); err == nil {
+		t.Error("Expected an error for an invalid alias")
+	}
+	if err := renameCmd.RunE(renameCmd, []string{"nope", "other"}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := renameCmd.RunE(renameCmd, []string{"proj", "api"}); err == nil {
+		t.Error("Expected an error renaming onto an existing alias without --force")
+	}
+
+	renameForce = true
+	if err := renameCmd.RunE(renameCmd, []string{"proj", "api"}); err != nil {
+		t.Fatalf("rename --force failed: %v", err)
+	}
+
+	store, _ = storage.NewStore()
+	bookmark, exists := store.GetBookmark("api")
+	if !exists || bookmark.Path != tempDir || !bookmark.Created.Equal(created) || bookmark.UsedCount != 1 {
+		t.Errorf("Expected proj's bookmark under the new alias, got %+v", bookmark)
+	}
+	if _, exists := store.GetBookmark("proj"); exists {
+		t.Error("Expected the old alias to be gone")
+	}
+
+	events, _ := store.UsageEvents(time.Time{}, time.Time{})
+	if len(events) != 1 || events[0].Alias != "api" {
+		t.Errorf("Expected only proj's use, now logged as api, got %+v", events)
+	}
+
+	trust, _ := storage.LoadTrust()
+	if !trust.Trusted("api", bookmark) {
+		t.Error("Expected the allowed hooks to move with the bookmark")
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+
+	"github.com/rethil/fast-nav/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -12,19 +16,92 @@ and quickly navigate to them using short aliases.
 
 Usage:
   fn save <alias>     Save current directory with an alias
+  fn open <alias>     Open a bookmark in your editor, file manager or IDE
+  fn exec <alias> cmd Run a command in a bookmarked directory
+  fn run <alias> task Run a task stored with fn task add
+  fn foreach -- cmd   Run a command in many bookmarked directories
   fn <alias>          Navigate to saved directory  
   fn list             List all saved aliases
   fn delete <alias>   Remove a saved alias
   fn path <alias>     Print path without navigating
   fn edit <alias>     Update existing alias to current directory
+  fn rename <old> <new> Rename an alias, keeping its history and stats
+  fn alias add <alias> <synonym> Give a bookmark another name
   fn cleanup          Remove bookmarks pointing to non-existent directories
   fn search <pattern> Find bookmarks by alias or path pattern
   fn recent [index]   Navigate to recently used bookmarks
-  fn uninstall        Uninstall fn and remove shell integration`,
+  fn ui               Manage bookmarks in a full-screen terminal UI
+  fn shell-hook <sh>  Print a hook that records visited directories
+  fn hook set <alias> Run shell snippets on entering a bookmark
+  fn suggest          Suggest bookmarks for frequently visited directories
+  fn back [n]         Go back in this shell's navigation stack (also: fn -)
+  fn forward [n]      Go forward in this shell's navigation stack
+  fn stack            Show this shell's navigation stack
+  fn init <shell>     Print shell integration code (eval "$(fn init bash)")
+  fn stats            Show bookmark usage over time
+  fn import           Import bookmarks from zoxide, autojump, z, fasd, ...
+  fn export [file]    Export bookmarks to JSON, CSV, TOML, YAML or text
+  fn env --shell <sh> Make bookmarks usable outside fn (~proj, cd proj)
+  fn sync             Sync bookmarks with other machines through git
+  fn merge <file>     Merge another copy of bookmarks.json into yours
+  fn uninstall        Uninstall fn and remove shell integration
+
+Exit codes:
+  0 success, 1 any other error, 2 alias not found, 3 directory missing,
+  4 permission denied, 5 ambiguous alias, 6 corrupt data file
+
+list, search, recent and cleanup honour --output json|jsonl|tsv|nul|template=...,
+and stats honours --output json; other commands reject it`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(); err != nil {
+			return err
+		}
+		return checkOutputSupported(cmd)
+	},
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// Exit codes, stable so that shell wrappers and scripts can rely on them
+const (
+	ExitOK           = 0
+	ExitError        = 1 // any other failure, including usage errors, unknown tasks and openers
+	ExitNotFound     = 2 // no bookmark matches the alias
+	ExitPathMissing  = 3 // the bookmarked directory no longer exists
+	ExitPermission   = 4 // permission denied
+	ExitAmbiguous    = 5 // the pattern matches several bookmarks
+	ExitCorruptStore = 6 // a file under ~/.fn could not be parsed
+)
+
+// Execute runs the command line and returns the process exit code
+func Execute() int {
+	// cobra drops a lone "-" as if it were a flag, so map 'fn -' to 'fn back' here
+	if len(os.Args) > 1 && os.Args[1] == "-" {
+		rootCmd.SetArgs(append([]string{"back"}, os.Args[2:]...))
+	}
+	return exitCode(rootCmd.Execute())
+}
+
+// exitCode maps an error returned by a command to its exit code
+func exitCode(err error) int {
+	var commandErr *commandExitError
+	if errors.As(err, &commandErr) {
+		return commandErr.code
+	}
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, storage.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, storage.ErrPathMissing):
+		return ExitPathMissing
+	case errors.Is(err, storage.ErrPermission):
+		return ExitPermission
+	case errors.Is(err, storage.ErrAmbiguous):
+		return ExitAmbiguous
+	case errors.Is(err, storage.ErrCorruptStore):
+		return ExitCorruptStore
+	}
+	return ExitError
 }
 
 func init() {
@@ -38,4 +115,35 @@ func init() {
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(recentCmd)
+	rootCmd.AddCommand(uiCmd)
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(shellHookCmd)
+	rootCmd.AddCommand(suggestCmd)
+	rootCmd.AddCommand(backCmd)
+	rootCmd.AddCommand(forwardCmd)
+	rootCmd.AddCommand(stackCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(sshCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(foreachCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(enterCmd)
+	rootCmd.AddCommand(taskCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(aliasCmd)
+
+	rootCmd.PersistentFlags().StringVar(&sessionID, "session", os.Getenv("FN_SESSION"),
+		"shell session id for the back/forward stack (set by the shell wrapper)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "", outputHelp)
+	for _, cmd := range []*cobra.Command{listCmd, searchCmd, recentCmd, cleanupCmd, statsCmd} {
+		honourOutput(cmd)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var validTaskName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.:-]*$`)
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Manage named commands stored on bookmarks",
+	Long: `Store the commands you run in a project under short names, then run them
+from anywhere with 'fn run <alias> <task>'.`,
+	Example: `  fn task add api dev 'docker compose up api'
+  fn task add api test go test ./...
+  fn run api dev`,
+}
+
+var taskAddCmd = &cobra.Command{
+	Use:   "add <alias> <name> <command...>",
+	Short: "Add or replace a task",
+	Long: `Add a task to a bookmark, replacing any task with the same name. The
+command is run by sh in the bookmark's directory; when it is given as several
+arguments they are joined with spaces. Arguments given to 'fn run' after the
+task name are available to it as "$@".`,
+	Args:              cobra.MinimumNArgs(3),
+	ValidArgsFunction: taskCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias, name, words := args[0], args[1], args[2:]
+		if words[0] == "--" {
+			words = words[1:]
+		}
+		command := strings.Join(words, " ")
+		if command == "" {
+			return fmt.Errorf("no command given")
+		}
+		if !validTaskName.MatchString(name) {
+			return fmt.Errorf("invalid task name: %s", name)
+		}
+
+		return updateTasks(alias, func(tasks map[string]string) error {
+			tasks[name] = command
+			return nil
+		}, fmt.Sprintf("✓ Added task '%s' to '%s'", name, alias))
+	},
+}
+
+var taskRemoveCmd = &cobra.Command{
+	Use:               "remove <alias> <name>",
+	Aliases:           []string{"rm"},
+	Short:             "Remove a task",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: taskCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias, name := args[0], args[1]
+		return updateTasks(alias, func(tasks map[string]string) error {
+			if _, exists := tasks[name]; !exists {
+				return fmt.Errorf("'%s' has no task '%s'", alias, name)
+			}
+			delete(tasks, name)
+			return nil
+		}, fmt.Sprintf("✓ Removed task '%s' from '%s'", name, alias))
+	},
+}
+
+// updateTasks edits a copy of a bookmark's tasks and stores the result,
+// allowing it like any other change the user makes to their own bookmark
+func updateTasks(alias string, edit func(map[string]string) error, done string) error {
+	store, err := storage.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	bookmark, exists := store.GetBookmark(alias)
+	if !exists {
+		return fmt.Errorf("%w: %s", storage.ErrNotFound, alias)
+	}
+	tasks := make(map[string]string, len(bookmark.Tasks))
+	for name, command := range bookmark.Tasks {
+		tasks[name] = command
+	}
+	if err := edit(tasks); err != nil {
+		return err
+	}
+
+	allowed, err := updateOnEnter(alias, func(store *storage.Store) error {
+		return store.SetTasks(alias, tasks)
+	})
+	if err != nil {
+		return err
+	}
+	color.Green(done)
+	warnNotAllowed(alias, allowed)
+	return nil
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <alias> [task] [args...]",
+	Short: "Run a bookmark's task in its directory, or list its tasks",
+	Long: `Run a task stored with 'fn task add' in the bookmark's directory, without
+leaving the current one. The alias is resolved like 'fn <alias>', including
+fuzzy matches. Without a task name, the bookmark's tasks are listed.
+
+The task shares this terminal, and fn exits with its exit status, as with
+'fn exec'. Tasks that arrived through import, merge or sync only run once
+they are allowed with 'fn hook allow'.`,
+	Example: `  fn run api
+  fn run api dev
+  fn run api test -run TestLogin`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: taskCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		pattern := args[0]
+		alias, bookmark, err := resolveBookmark(store, pattern)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			printTasks(alias, bookmark)
+			return nil
+		}
+
+		// Past this point the arguments were understood
+		cmd.SilenceUsage = true
+		name := args[1]
+		command, exists := bookmark.Tasks[name]
+		if !exists {
+			return fmt.Errorf("'%s' has no task '%s'; run 'fn run %s' to list its tasks", alias, name, alias)
+		}
+		if storage.IsRemote(bookmark.Path) {
+			return fmt.Errorf("cannot run tasks in remote bookmark '%s'", alias)
+		}
+		trust, err := storage.LoadTrust()
+		if err != nil {
+			return err
+		}
+		if !trust.Trusted(alias, bookmark) {
+			return fmt.Errorf("tasks of '%s' are not allowed; review them with 'fn hook show %s', then run 'fn hook allow %s'", alias, alias, alias)
+		}
+		dir := bookmark.Dir()
+		err = storage.CheckDir(dir)
+		if err != nil {
+			return err
+		}
+
+		store.UpdateUsageWith(alias, resolutionFor(store, alias, pattern))
+
+		extra := args[2:]
+		if len(extra) > 0 && extra[0] == "--" {
+			extra = extra[1:]
+		}
+		err = runIn(dir, append([]string{"sh", "-c", command, name}, extra...))
+		var exitErr *commandExitError
+		if errors.As(err, &exitErr) {
+			cmd.SilenceErrors = true
+		}
+		return err
+	},
+}
+
+func printTasks(alias string, bookmark *storage.Bookmark) {
+	if len(bookmark.Tasks) == 0 {
+		fmt.Printf("No tasks for '%s'; add one with 'fn task add %s <name> <command>'\n", alias, alias)
+		return
+	}
+
+	names := sortedKeys(bookmark.Tasks)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	color.New(color.Bold).Printf("Tasks for %s (%s):\n", alias, bookmark.Path)
+	for _, name := range names {
+		fmt.Printf("  %s  %s\n", color.YellowString("%-*s", width, name), bookmark.Tasks[name])
+	}
+}
+
+// taskCompletion completes the alias, then the names of its tasks, then
+// files for the task's own arguments
+func taskCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return nameCompletionFunc(cmd, args, toComplete)
+	case 1:
+		store, err := storage.NewStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		_, bookmark, err := store.Resolve(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var completions []string
+		for _, name := range sortedKeys(bookmark.Tasks) {
+			completions = append(completions, name+"\t"+bookmark.Tasks[name])
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+func init() {
+	// Flags after the alias belong to the task
+	runCmd.Flags().SetInterspersed(false)
+	taskAddCmd.Flags().SetInterspersed(false)
+
+	taskCmd.AddCommand(taskAddCmd, taskRemoveCmd)
+}
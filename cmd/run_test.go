@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestTasks(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("api", tempDir)
+
+	if err := taskAddCmd.RunE(taskAddCmd, []string{"api", "touch", "--", "touch", `"$1"`}); err != nil {
+		t.Fatalf("task add failed: %v", err)
+	}
+	if err := taskAddCmd.RunE(taskAddCmd, []string{"api", "fail", "exit 3"}); err != nil {
+		t.Fatalf("task add failed: %v", err)
+	}
+	if err := taskAddCmd.RunE(taskAddCmd, []string{"api", "bad name", "true"}); err == nil {
+		t.Error("Expected an error for an invalid task name")
+	}
+
+	if err := runCmd.RunE(runCmd, []string{"ap", "touch", "--", "marker"}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "marker")); err != nil {
+		t.Errorf("Expected the task to run in the bookmark with its arguments: %v", err)
+	}
+
+	err := runCmd.RunE(runCmd, []string{"api", "fail"})
+	if exitCode(err) != 3 {
+		t.Errorf("Expected exit code 3, got %d (%v)", exitCode(err), err)
+	}
+	if err := runCmd.RunE(runCmd, []string{"api", "missing"}); err == nil || exitCode(err) != ExitError {
+		t.Errorf("Expected an unknown task to exit 1, got %v", err)
+	}
+	if err := runCmd.RunE(runCmd, []string{"nope", "touch"}); exitCode(err) != ExitNotFound {
+		t.Errorf("Expected an unknown alias to exit 2, got %v", err)
+	}
+
+	completions, _ := taskCompletion(runCmd, []string{"api"}, "")
+	if len(completions) != 2 || completions[0] != "fail\texit 3" {
+		t.Errorf("Expected task completions, got %q", completions)
+	}
+
+	if err := taskRemoveCmd.RunE(taskRemoveCmd, []string{"api", "fail"}); err != nil {
+		t.Fatalf("task remove failed: %v", err)
+	}
+	store, _ = storage.NewStore()
+	if bookmark, _ := store.GetBookmark("api"); len(bookmark.Tasks) != 1 || bookmark.Tasks["touch"] != `touch "$1"` {
+		t.Errorf("Expected only the touch task to remain, got %v", bookmark.Tasks)
+	}
+	if err := taskRemoveCmd.RunE(taskRemoveCmd, []string{"api", "fail"}); err == nil {
+		t.Error("Expected an error removing a missing task")
+	}
+}
+
+func TestImportedTasksNeedAllowing(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	// As if it arrived through fn import, merge or sync
+	store, _ := storage.NewStore()
+	store.PutBookmark("api", &storage.Bookmark{Path: tempDir, Tasks: map[string]string{"touch": "touch marker"}})
+
+	err := runCmd.RunE(runCmd, []string{"api", "touch"})
+	if err == nil || !strings.Contains(err.Error(), "fn hook allow api") {
+		t.Fatalf("Expected an imported task to be refused, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "marker")); err == nil {
+		t.Fatal("Expected the refused task not to run")
+	}
+
+	if err := hookAllowCmd.RunE(hookAllowCmd, []string{"api"}); err != nil {
+		t.Fatalf("hook allow failed: %v", err)
+	}
+	if err := runCmd.RunE(runCmd, []string{"api", "touch"}); err != nil {
+		t.Fatalf("Expected an allowed task to run, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "marker")); err != nil {
+		t.Errorf("Expected the allowed task to run: %v", err)
+	}
+}
@@ -3,16 +3,32 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/rethil/fast-nav/internal/remote"
 	"github.com/rethil/fast-nav/internal/storage"
 	"github.com/spf13/cobra"
 )
 
+var saveRemote string
+
 var saveCmd = &cobra.Command{
-	Use:   "save <alias>",
+	Use:   "save <alias> [path]",
 	Short: "Save current directory with an alias",
-	Args:  cobra.ExactArgs(1),
+	Long: `Save the current directory, or the given directory or file, under an
+alias.
+
+Jumping to a file bookmark goes to the directory containing it, and
+'fn open <alias>' opens the file in your editor.
+
+With --remote, save a directory on another host instead. Jumping to it
+opens an ssh session there, started in that directory.`,
+	Example: `  fn save api
+  fn save runbook ~/ops/runbook.md
+  fn save build --remote ssh://me@build1:2222/srv/build`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		alias := args[0]
 
@@ -21,10 +37,35 @@ var saveCmd = &cobra.Command{
 			return fmt.Errorf("invalid alias: use only alphanumeric characters, dash, and underscore")
 		}
 
-		// Get current directory
-		currentDir, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+		// Get current directory, the given path, or the remote target
+		var currentDir string
+		file := false
+		switch {
+		case saveRemote != "":
+			if len(args) == 2 {
+				return fmt.Errorf("--remote cannot be combined with a path")
+			}
+			target, err := remote.Parse(saveRemote)
+			if err != nil {
+				return err
+			}
+			currentDir = target.String()
+		case len(args) == 2:
+			path, err := filepath.Abs(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to resolve path: %w", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("failed to access %s: %w", path, err)
+			}
+			currentDir, file = path, !info.IsDir()
+		default:
+			var err error
+			currentDir, err = os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
 		}
 
 		// Save bookmark
@@ -32,8 +73,15 @@ var saveCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to initialize storage: %w", err)
 		}
+		if primary, taken := store.GetSynonyms()[alias]; taken {
+			return fmt.Errorf("'%s' is a synonym of '%s'; remove it with 'fn alias rm %s' first", alias, primary, alias)
+		}
 
-		err = store.SaveBookmark(alias, currentDir)
+		if file {
+			err = store.SaveFileBookmark(alias, currentDir)
+		} else {
+			err = store.SaveBookmark(alias, currentDir)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to save bookmark: %w", err)
 		}
@@ -52,6 +100,18 @@ func isValidAlias(alias string) bool {
 		}
 	}
 
+	// Any command name would be routed to the command by the shell wrapper
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == alias || c.HasAlias(alias) {
+			return false
+		}
+	}
+
+	// A leading dash would be parsed as a flag
+	if strings.HasPrefix(alias, "-") {
+		return false
+	}
+
 	// Check format: alphanumeric + dash/underscore, max 50 chars
 	if len(alias) > 50 {
 		return false
@@ -60,3 +120,7 @@ func isValidAlias(alias string) bool {
 	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_-]+$`, alias)
 	return matched
 }
+
+func init() {
+	saveCmd.Flags().StringVar(&saveRemote, "remote", "", "save an ssh://[user@]host[:port]/path target instead of the current directory")
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -33,6 +35,15 @@ var searchCmd = &cobra.Command{
 				matches = append(matches, alias)
 			}
 		}
+		sort.Strings(matches)
+
+		if machineOutput() {
+			var records []bookmarkRecord
+			for _, alias := range matches {
+				records = append(records, newBookmarkRecord(alias, bookmarks[alias]))
+			}
+			return writeRecords(os.Stdout, records)
+		}
 
 		if len(matches) == 0 {
 			color.Red("No bookmarks found matching '%s'", pattern)
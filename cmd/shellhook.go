@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var shellHookCmd = &cobra.Command{
+	Use:   "shell-hook [bash|zsh|fish|tcsh]",
+	Short: "Print a shell hook that records visited directories",
+	Long: `Print a shell hook that records every directory you visit, so fn can
+learn from plain 'cd' as well as from bookmarks.
+
+Bash (~/.bashrc):
+  eval "$(fn shell-hook bash)"
+
+Zsh (~/.zshrc):
+  eval "$(fn shell-hook zsh)"
+
+Fish (~/.config/fish/config.fish):
+  fn shell-hook fish | source
+
+Tcsh (~/.tcshrc):
+  fn shell-hook tcsh > ~/.fn-hook.tcsh
+  source ~/.fn-hook.tcsh
+
+'fn init' already includes this hook unless --no-record is given.`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "tcsh"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hook, err := recordHook(args[0], binaryName())
+		if err != nil {
+			return err
+		}
+		fmt.Print(hook)
+		return nil
+	},
+}
+
+// binaryName is the name the fn binary was invoked as, so generated shell
+// code calls the same binary even when it is installed as e.g. fast-nav
+func binaryName() string {
+	return filepath.Base(os.Args[0])
+}
+
+// recordHook returns shell code that runs 'fn _record' whenever the working
+// directory changes
+func recordHook(shell, binary string) (string, error) {
+	switch shell {
+	case "bash":
+		return fmt.Sprintf(`__fn_record() {
+    if [ "$PWD" != "${__fn_last_pwd:-}" ]; then
+        __fn_last_pwd="$PWD"
+        command %[1]s _record "$PWD" >/dev/null 2>&1
+    fi
+}
+case ";${PROMPT_COMMAND:-};" in
+    *";__fn_record;"*) ;;
+    *) PROMPT_COMMAND="__fn_record${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`, binary), nil
+	case "zsh":
+		return fmt.Sprintf(`__fn_record() {
+    command %[1]s _record "$PWD" >/dev/null 2>&1
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd __fn_record
+`, binary), nil
+	case "fish":
+		return fmt.Sprintf(`function __fn_record --on-variable PWD
+    command %[1]s _record "$PWD" >/dev/null 2>&1
+end
+`, binary), nil
+	case "tcsh":
+		// env keeps a wrapper alias of the same name from being expanded
+		return fmt.Sprintf(`alias cwdcmd 'env %[1]s _record "$cwd" >& /dev/null'
+`, binary), nil
+	}
+	return "", fmt.Errorf("unsupported shell: %s", shell)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/rethil/fast-nav/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+var sshCmd = &cobra.Command{
+	Use:    "_ssh <ssh://target>",
+	Short:  "Open a shell in a remote bookmark (used by the shell wrapper)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := remote.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		// ssh reports its own errors, and passes on the remote shell's
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return target.Open()
+	},
+}
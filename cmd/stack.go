@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var backCmd = &cobra.Command{
+	Use:         "back [n]",
+	Short:       "Go back n directories in this session's navigation stack",
+	Annotations: map[string]string{cdAnnotation: "true"},
+	Long: `Go back n directories (default 1) in this shell session's navigation stack.
+
+'fn -' is shorthand for 'fn back'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := stepCount(args)
+		if err != nil {
+			return err
+		}
+		return stepBack(n)
+	},
+}
+
+var forwardCmd = &cobra.Command{
+	Use:         "forward [n]",
+	Short:       "Go forward n directories in this session's navigation stack",
+	Annotations: map[string]string{cdAnnotation: "true"},
+	Args:        cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := stepCount(args)
+		if err != nil {
+			return err
+		}
+
+		session, err := openSession()
+		if err != nil {
+			return err
+		}
+
+		path, err := session.Forward(n)
+		if err != nil {
+			return err
+		}
+
+		if err := checkPrintable(path); err != nil {
+			return err
+		}
+		fmt.Print(path)
+		return nil
+	},
+}
+
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Show this session's navigation stack",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session, err := openSession()
+		if err != nil {
+			return err
+		}
+
+		entries := session.Entries()
+		if len(entries) == 0 {
+			fmt.Println("Navigation stack is empty")
+			return nil
+		}
+
+		// Newest first, with offsets usable as 'fn back n' / 'fn forward n'
+		green := color.New(color.FgGreen)
+		gray := color.New(color.FgHiBlack)
+		for i := len(entries) - 1; i >= 0; i-- {
+			offset := i - session.Index()
+			switch {
+			case offset == 0:
+				green.Printf("→ %3s  %s\n", "", entries[i])
+			case offset > 0:
+				gray.Printf("  %+3d  %s\n", offset, entries[i])
+			default:
+				fmt.Printf("  %+3d  %s\n", offset, entries[i])
+			}
+		}
+		return nil
+	},
+}
+
+// stepCount parses the optional [n] argument of back/forward
+func stepCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid step count: %s", args[0])
+	}
+	return n, nil
+}
+
+func stepBack(n int) error {
+	session, err := openSession()
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	path, err := session.Back(cwd, n)
+	if err != nil {
+		return err
+	}
+	if err := checkPrintable(path); err != nil {
+		return err
+	}
+
+	fmt.Print(path)
+	return nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsSince string
+	statsUntil string
+	statsBy    string
+	statsTop   int
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show bookmark usage over time",
+	Long: `Show which bookmarks you jump to most, how jumps are spread over time,
+how many bookmarks are never used or point at missing directories, and how
+often aliases are resolved by fuzzy matching rather than typed exactly.
+
+--since and --until take an age such as 7d, 2w or 12h, or a date such as
+2024-01-31. The window defaults to the last 30 days.`,
+	Example: `  fn stats
+  fn stats --since 2w --by week
+  fn stats --since 2024-01-01 --until 2024-02-01 --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if machineOutput() && outputFormat != "json" {
+			return fmt.Errorf("fn stats supports --output json only")
+		}
+		if statsBy != "day" && statsBy != "week" {
+			return fmt.Errorf("invalid --by value: %s (want day or week)", statsBy)
+		}
+
+		now := time.Now()
+		since, err := parseTimeFlag(statsSince, now)
+		if err != nil {
+			return err
+		}
+		until := now
+		if statsUntil != "" {
+			until, err = parseTimeFlag(statsUntil, now)
+			if err != nil {
+				return err
+			}
+		}
+		if !since.Before(until) {
+			return fmt.Errorf("--since must be before --until")
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		events, err := store.UsageEvents(since, until)
+		if err != nil {
+			return err
+		}
+
+		stats := computeUsageStats(store.GetAllBookmarks(), events, since, until, statsBy, statsTop)
+		if machineOutput() {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(stats)
+		}
+
+		printUsageStats(os.Stdout, stats)
+		return nil
+	},
+}
+
+// usageStats is what fn stats reports; it is also its JSON schema
+type usageStats struct {
+	Since       time.Time                  `json:"since"`
+	Until       time.Time                  `json:"until"`
+	TotalJumps  int                        `json:"total_jumps"`
+	Resolutions map[storage.Resolution]int `json:"resolutions"`
+	// FuzzyRate is the share of alias lookups (exact + fuzzy) that needed
+	// fuzzy matching
+	FuzzyRate float64       `json:"fuzzy_rate"`
+	Top       []aliasJumps  `json:"top"`
+	Bucket    string        `json:"bucket"`
+	Buckets   []bucketJumps `json:"buckets"`
+	NeverUsed int           `json:"never_used"`
+	Dead      int           `json:"dead"`
+}
+
+type aliasJumps struct {
+	Alias string `json:"alias"`
+	Jumps int    `json:"jumps"`
+}
+
+type bucketJumps struct {
+	Start time.Time `json:"start"`
+	Jumps int       `json:"jumps"`
+}
+
+func computeUsageStats(bookmarks map[string]*storage.Bookmark, events []storage.UsageEvent, since, until time.Time, bucket string, top int) usageStats {
+	stats := usageStats{
+		Since:       since,
+		Until:       until,
+		TotalJumps:  len(events),
+		Resolutions: make(map[storage.Resolution]int),
+		Top:         []aliasJumps{},
+		Bucket:      bucket,
+	}
+
+	for start := bucketStart(since, bucket); start.Before(until); start = nextBucket(start, bucket) {
+		stats.Buckets = append(stats.Buckets, bucketJumps{Start: start})
+	}
+
+	perAlias := make(map[string]int)
+	for _, event := range events {
+		stats.Resolutions[event.Resolution]++
+		perAlias[event.Alias]++
+		for i := len(stats.Buckets) - 1; i >= 0; i-- {
+			if !event.Time.Before(stats.Buckets[i].Start) {
+				stats.Buckets[i].Jumps++
+				break
+			}
+		}
+	}
+
+	lookups := stats.Resolutions[storage.ResolutionExact] + stats.Resolutions[storage.ResolutionFuzzy]
+	if lookups > 0 {
+		stats.FuzzyRate = float64(stats.Resolutions[storage.ResolutionFuzzy]) / float64(lookups)
+	}
+
+	for alias, jumps := range perAlias {
+		stats.Top = append(stats.Top, aliasJumps{Alias: alias, Jumps: jumps})
+	}
+	sort.Slice(stats.Top, func(i, j int) bool {
+		if stats.Top[i].Jumps != stats.Top[j].Jumps {
+			return stats.Top[i].Jumps > stats.Top[j].Jumps
+		}
+		return stats.Top[i].Alias < stats.Top[j].Alias
+	})
+	if top > 0 && len(stats.Top) > top {
+		stats.Top = stats.Top[:top]
+	}
+
+	for _, bookmark := range bookmarks {
+		if bookmark.UsedCount == 0 {
+			stats.NeverUsed++
+		}
+		if storage.Missing(bookmark.Path) {
+			stats.Dead++
+		}
+	}
+
+	return stats
+}
+
+// bucketStart returns the local midnight (or Monday midnight for weeks)
+// at or before t
+func bucketStart(t time.Time, bucket string) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if bucket == "week" {
+		offset := (int(start.Weekday()) + 6) % 7 // days since Monday
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+func nextBucket(start time.Time, bucket string) time.Time {
+	if bucket == "week" {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// parseTimeFlag accepts an age relative to now (7d, 12h) or a date
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if age, err := storage.ParseAge(value); err == nil {
+		return now.Add(-age), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use an age like 7d or a date like 2024-01-31)", value)
+}
+
+// sparkline draws counts as a row of block characters scaled to the maximum
+func sparkline(counts []int) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+
+	max := 0
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+
+	line := make([]rune, len(counts))
+	for i, count := range counts {
+		level := 0
+		if max > 0 {
+			level = count * (len(levels) - 1) / max
+		}
+		line[i] = levels[level]
+	}
+	return string(line)
+}
+
+func printUsageStats(w io.Writer, stats usageStats) {
+	bold := color.New(color.Bold)
+	yellow := color.New(color.FgYellow)
+	gray := color.New(color.FgHiBlack)
+
+	bold.Fprintf(w, "Usage from %s to %s\n\n", stats.Since.Format("2006-01-02"), stats.Until.Format("2006-01-02"))
+
+	fmt.Fprintf(w, "Jumps: %d", stats.TotalJumps)
+	if stats.TotalJumps > 0 {
+		gray.Fprintf(w, " (exact %d, fuzzy %d, recent %d, cd %d)",
+			stats.Resolutions[storage.ResolutionExact], stats.Resolutions[storage.ResolutionFuzzy],
+			stats.Resolutions[storage.ResolutionRecent], stats.Resolutions[storage.ResolutionCd])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Fuzzy resolution rate: %.0f%%\n\n", stats.FuzzyRate*100)
+
+	counts := make([]int, len(stats.Buckets))
+	max := 0
+	for i, bucket := range stats.Buckets {
+		counts[i] = bucket.Jumps
+		if bucket.Jumps > max {
+			max = bucket.Jumps
+		}
+	}
+	fmt.Fprintf(w, "Jumps per %s:\n", stats.Bucket)
+	fmt.Fprintf(w, "  %s", sparkline(counts))
+	gray.Fprintf(w, "  max %d\n\n", max)
+
+	if len(stats.Top) > 0 {
+		fmt.Fprintln(w, "Top bookmarks:")
+		for i, entry := range stats.Top {
+			fmt.Fprintf(w, "  %2d. ", i+1)
+			yellow.Fprintf(w, "%-15s", entry.Alias)
+			fmt.Fprintf(w, " %d\n", entry.Jumps)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Never used: %d\n", stats.NeverUsed)
+	fmt.Fprintf(w, "Dead: %d", stats.Dead)
+	if stats.Dead > 0 {
+		gray.Fprint(w, " (run 'fn cleanup' to remove them)")
+	}
+	fmt.Fprintln(w)
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsSince, "since", "30d", "start of the window: an age (7d, 2w) or a date (2024-01-31)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "end of the window (default now)")
+	statsCmd.Flags().StringVar(&statsBy, "by", "day", "sparkline resolution: day or week")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "number of top bookmarks to show")
+}
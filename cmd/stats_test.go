@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestComputeUsageStats(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	until := since.AddDate(0, 0, 3)
+	day := func(n int) time.Time { return since.AddDate(0, 0, n) }
+
+	bookmarks := map[string]*storage.Bookmark{
+		"api":   {Path: t.TempDir(), UsedCount: 3},
+		"web":   {Path: "/nonexistent/fn-stats-test", UsedCount: 1},
+		"never": {Path: t.TempDir()},
+	}
+	events := []storage.UsageEvent{
+		{Time: day(0), Alias: "api", Resolution: storage.ResolutionExact},
+		{Time: day(0), Alias: "api", Resolution: storage.ResolutionFuzzy},
+		{Time: day(2), Alias: "api", Resolution: storage.ResolutionCd},
+		{Time: day(2), Alias: "web", Resolution: storage.ResolutionExact},
+	}
+
+	stats := computeUsageStats(bookmarks, events, since, until, "day", 1)
+
+	if stats.TotalJumps != 4 {
+		t.Errorf("Expected 4 jumps, got %d", stats.TotalJumps)
+	}
+	if stats.FuzzyRate < 0.33 || stats.FuzzyRate > 0.34 {
+		t.Errorf("Expected fuzzy rate 1/3, got %f", stats.FuzzyRate)
+	}
+	if len(stats.Top) != 1 || stats.Top[0].Alias != "api" || stats.Top[0].Jumps != 3 {
+		t.Errorf("Expected api with 3 jumps on top, got %+v", stats.Top)
+	}
+	if stats.NeverUsed != 1 || stats.Dead != 1 {
+		t.Errorf("Expected 1 never-used and 1 dead bookmark, got %d and %d", stats.NeverUsed, stats.Dead)
+	}
+
+	var counts []int
+	for _, bucket := range stats.Buckets {
+		counts = append(counts, bucket.Jumps)
+	}
+	if len(counts) != 4 || counts[0] != 2 || counts[1] != 0 || counts[2] != 2 {
+		t.Errorf("Unexpected per-day counts: %v", counts)
+	}
+	if got := sparkline(counts); got != "█▁█▁" {
+		t.Errorf("Unexpected sparkline %q", got)
+	}
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	got, err := parseTimeFlag("7d", now)
+	if err != nil || !got.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("parseTimeFlag(7d) = %v, %v", got, err)
+	}
+
+	got, err = parseTimeFlag("2024-01-31", now)
+	if err != nil || got.Year() != 2024 || got.Month() != time.January || got.Day() != 31 {
+		t.Errorf("parseTimeFlag(2024-01-31) = %v, %v", got, err)
+	}
+
+	if _, err := parseTimeFlag("yesterday", now); err == nil {
+		t.Error("Expected error for unparseable time")
+	}
+}
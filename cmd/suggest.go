@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	suggestInteractive bool
+	suggestLimit       int
+	suggestMinVisits   int
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest bookmarks for frequently visited directories",
+	Long: `List directories you keep returning to that are not bookmarked yet,
+ranked by frecency (visit count weighted by how recently you were there).
+
+Visits are recorded by the shell hook (see 'fn shell-hook'). Use -i to
+accept, rename or permanently ignore each suggestion.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		history, err := storage.NewHistory()
+		if err != nil {
+			return fmt.Errorf("failed to initialize history: %w", err)
+		}
+
+		suggestions := collectSuggestions(store, history, suggestMinVisits, suggestLimit)
+		if len(suggestions) == 0 {
+			fmt.Println("No suggestions yet. Keep navigating and check back later.")
+			return nil
+		}
+
+		if !suggestInteractive {
+			color.Cyan("💡 Suggested bookmarks:")
+			for _, s := range suggestions {
+				fmt.Printf("  %-15s → %s (visited %d times)\n", s.alias, s.dir.Path, s.dir.Visit.Count)
+			}
+			fmt.Println()
+			fmt.Println("Use 'fn suggest -i' to accept, rename or ignore them.")
+			return nil
+		}
+
+		return reviewSuggestions(store, history, suggestions)
+	},
+}
+
+// suggestion is an unbookmarked directory with a proposed alias
+type suggestion struct {
+	dir   storage.DirScore
+	alias string
+}
+
+// collectSuggestions picks the top unbookmarked, still existing directories
+func collectSuggestions(store *storage.Store, history *storage.History, minVisits, limit int) []suggestion {
+	home, _ := homedir.Dir()
+	taken := make(map[string]bool)
+	synonyms := store.GetSynonyms()
+
+	var suggestions []suggestion
+	for _, dir := range history.Ranked() {
+		if limit > 0 && len(suggestions) >= limit {
+			break
+		}
+		if dir.Visit.Count < minVisits || dir.Path == home || dir.Path == "/" {
+			continue
+		}
+		if len(store.FindByPath(dir.Path)) > 0 {
+			continue
+		}
+		if info, err := os.Stat(dir.Path); err != nil || !info.IsDir() {
+			continue
+		}
+
+		alias := proposeAlias(dir.Path, func(a string) bool {
+			_, exists := store.GetBookmark(a)
+			_, isSynonym := synonyms[a]
+			return exists || isSynonym || taken[a]
+		})
+		if alias == "" {
+			continue
+		}
+		taken[alias] = true
+		suggestions = append(suggestions, suggestion{dir: dir, alias: alias})
+	}
+
+	return suggestions
+}
+
+var invalidAliasChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// proposeAlias derives a valid, unused alias from a directory path. It starts
+// from the base name, falls back to "parent-base", then numbers the result.
+func proposeAlias(dir string, taken func(string) bool) string {
+	sanitize := func(name string) string {
+		name = invalidAliasChars.ReplaceAllString(strings.ToLower(name), "-")
+		name = strings.Trim(name, "-")
+		if len(name) > 50 {
+			name = strings.TrimRight(name[:50], "-")
+		}
+		return name
+	}
+
+	base := sanitize(filepath.Base(dir))
+	candidates := []string{base}
+	if parent := sanitize(filepath.Base(filepath.Dir(dir))); parent != "" {
+		candidates = append(candidates, sanitize(parent+"-"+base))
+	}
+
+	for _, candidate := range candidates {
+		if isValidAlias(candidate) && !taken(candidate) {
+			return candidate
+		}
+	}
+
+	if base == "" {
+		base = "dir"
+	}
+	for i := 2; i < 100; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		stem := base
+		if len(stem)+len(suffix) > 50 {
+			stem = stem[:50-len(suffix)]
+		}
+		candidate := stem + suffix
+		if isValidAlias(candidate) && !taken(candidate) {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// reviewSuggestions walks through the suggestions one by one
+func reviewSuggestions(store *storage.Store, history *storage.History, suggestions []suggestion) error {
+	const (
+		actionAccept = "Accept"
+		actionRename = "Rename"
+		actionIgnore = "Ignore forever"
+		actionSkip   = "Skip"
+		actionStop   = "Stop"
+	)
+
+	saved := 0
+	for _, s := range suggestions {
+		action := ""
+		prompt := &survey.Select{
+			Message: fmt.Sprintf("%s (visited %d times) → '%s'?", s.dir.Path, s.dir.Visit.Count, s.alias),
+			Options: []string{actionAccept, actionRename, actionIgnore, actionSkip, actionStop},
+		}
+		err := survey.AskOne(prompt, &action)
+		if err != nil {
+			return err
+		}
+
+		alias := s.alias
+		switch action {
+		case actionStop:
+			fmt.Printf("✓ Saved %d bookmark(s)\n", saved)
+			return nil
+		case actionSkip:
+			continue
+		case actionIgnore:
+			err = history.Ignore(s.dir.Path)
+			if err != nil {
+				return fmt.Errorf("failed to ignore directory: %w", err)
+			}
+			continue
+		case actionRename:
+			err = survey.AskOne(&survey.Input{Message: "Alias:", Default: s.alias}, &alias,
+				survey.WithValidator(func(ans interface{}) error {
+					name, _ := ans.(string)
+					if !isValidAlias(name) {
+						return fmt.Errorf("invalid alias: use only alphanumeric characters, dash, and underscore")
+					}
+					if _, exists := store.GetBookmark(name); exists {
+						return fmt.Errorf("alias '%s' already exists", name)
+					}
+					if primary, taken := store.GetSynonyms()[name]; taken {
+						return fmt.Errorf("'%s' is a synonym of '%s'", name, primary)
+					}
+					return nil
+				}))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = store.SaveBookmark(alias, s.dir.Path)
+		if err != nil {
+			return fmt.Errorf("failed to save bookmark: %w", err)
+		}
+		color.Green("✓ Saved '%s' → %s", alias, s.dir.Path)
+		saved++
+	}
+
+	fmt.Printf("✓ Saved %d bookmark(s)\n", saved)
+	return nil
+}
+
+func init() {
+	suggestCmd.Flags().BoolVarP(&suggestInteractive, "interactive", "i", false, "accept, rename or ignore each suggestion")
+	suggestCmd.Flags().IntVarP(&suggestLimit, "limit", "n", 10, "maximum number of suggestions")
+	suggestCmd.Flags().IntVar(&suggestMinVisits, "min-visits", 3, "only suggest directories visited at least this often")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestProposeAlias(t *testing.T) {
+	none := func(string) bool { return false }
+
+	tests := []struct {
+		dir      string
+		taken    func(string) bool
+		expected string
+	}{
+		{"/home/user/src/My Project", none, "my-project"},
+		{"/home/user/src/api", func(a string) bool { return a == "api" }, "src-api"},
+		{"/srv/list", none, "srv-list"},
+		{"/a/b/c", func(a string) bool { return a == "c" || a == "b-c" }, "c-2"},
+		{"/x/" + strings.Repeat("long", 20), none, strings.Repeat("long", 12) + "lo"},
+	}
+
+	for _, tt := range tests {
+		got := proposeAlias(tt.dir, tt.taken)
+		if got != tt.expected {
+			t.Errorf("proposeAlias(%q) = %q, expected %q", tt.dir, got, tt.expected)
+		}
+		if !isValidAlias(got) {
+			t.Errorf("proposeAlias(%q) returned invalid alias %q", tt.dir, got)
+		}
+	}
+}
+
+func TestCollectSuggestions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fn-suggest-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	frequent := filepath.Join(tempDir, "frequent")
+	bookmarked := filepath.Join(tempDir, "bookmarked")
+	rare := filepath.Join(tempDir, "rare")
+	for _, dir := range []string{frequent, bookmarked, rare} {
+		os.MkdirAll(dir, 0755)
+	}
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("bm", bookmarked)
+
+	history, _ := storage.NewHistory()
+	for i := 0; i < 3; i++ {
+		history.RecordVisit(frequent)
+		history.RecordVisit(bookmarked)
+		history.RecordVisit(tempDir)
+	}
+	history.RecordVisit(rare)
+
+	suggestions := collectSuggestions(store, history, 3, 10)
+	if len(suggestions) != 1 {
+		t.Fatalf("Expected 1 suggestion, got %d: %v", len(suggestions), suggestions)
+	}
+	if suggestions[0].dir.Path != frequent || suggestions[0].alias != "frequent" {
+		t.Errorf("Unexpected suggestion: %s → %s", suggestions[0].alias, suggestions[0].dir.Path)
+	}
+
+	// A synonym is as taken as an alias, or saving the suggestion would fail
+	store.AddSynonym("bm", "frequent")
+	suggestions = collectSuggestions(store, history, 3, 10)
+	if len(suggestions) != 1 || suggestions[0].alias == "frequent" {
+		t.Errorf("Expected the synonym to be avoided, got %v", suggestions)
+	}
+	if err := store.SaveBookmark(suggestions[0].alias, frequent); err != nil {
+		t.Errorf("Expected the proposed alias to save, got %v", err)
+	}
+}
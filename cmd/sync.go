@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/gitsync"
+	"github.com/rethil/fast-nav/internal/merge"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync bookmarks with other machines through a git repository",
+	Long: `Merge bookmarks with a git repository shared between machines, then push
+the result. Set it up once per machine with 'fn sync init <git-remote>'.
+
+Aliases, synonyms, paths, tags, openers, hooks, variables and tasks are
+synced; usage counts, timestamps and permission to apply hooks, variables
+and tasks stay on each machine. Edits are merged alias by alias against the last sync, so a
+bookmark added on one machine and another deleted elsewhere both carry
+over. If two machines point the same alias at different paths, this
+machine's path is kept and the conflict is reported.`,
+	Example: `  fn sync init git@github.com:me/bookmarks.git
+  fn sync`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := gitsync.Dir()
+		if err != nil {
+			return err
+		}
+		repo, err := gitsync.Open(dir)
+		if err != nil {
+			return err
+		}
+		return runSync(repo)
+	},
+}
+
+var syncInitCmd = &cobra.Command{
+	Use:   "init <git-remote>",
+	Short: "Set up syncing with a git repository and sync once",
+	Long: `Clone a git repository to ~/.fn/sync and sync with it. Any remote git
+accepts works, including an empty bare repository or a local path.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := gitsync.Dir()
+		if err != nil {
+			return err
+		}
+		repo, err := gitsync.Init(dir, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to set up sync: %w", err)
+		}
+		fmt.Printf("Syncing with %s\n", repo.Remote())
+		return runSync(repo)
+	},
+}
+
+func runSync(repo *gitsync.Repo) error {
+	store, err := storage.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	// The merged result is saved locally before it is committed, so a
+	// failure here leaves the sync clone where it was
+	var added, updated, removed []string
+	apply := func(report *gitsync.Report) error {
+		return store.Batch(func() error {
+			// Synonyms go first, so that none of them blocks a synced alias
+			if err := store.SetSynonyms(nil); err != nil {
+				return err
+			}
+			var err error
+			added, updated, removed, err = applySynced(store, report.Bookmarks)
+			if err != nil {
+				return err
+			}
+			if err := store.SetSynonyms(report.Synonyms); err != nil {
+				return fmt.Errorf("failed to save synonyms: %w", err)
+			}
+			return nil
+		})
+	}
+
+	host, _ := os.Hostname()
+	report, err := repo.Sync(store.GetAllBookmarks(), store.GetSynonyms(), fmt.Sprintf("Sync from %s", host), apply)
+	if err != nil {
+		return fmt.Errorf("failed to sync: %w", err)
+	}
+
+	printSyncReport(added, updated, removed, report)
+	return nil
+}
+
+// applySynced makes the store match the synced bookmarks, keeping local
+// usage statistics for bookmarks whose path did not change
+func applySynced(store *storage.Store, synced map[string]*storage.Bookmark) (added, updated, removed []string, err error) {
+	for alias := range store.GetAllBookmarks() {
+		if synced[alias] == nil {
+			removed = append(removed, alias)
+		}
+	}
+	for _, alias := range removed {
+		if err := store.DeleteBookmark(alias); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to delete bookmark '%s': %w", alias, err)
+		}
+	}
+
+	now := time.Now()
+	for alias, bookmark := range synced {
+		existing, exists := store.GetBookmark(alias)
+		if exists && merge.Equal(existing, bookmark) {
+			continue
+		}
+
+		// Everything synced is taken as is; only usage data is local
+		applied := *bookmark
+		applied.Created, applied.LastUsed, applied.UsedCount = now, now, 0
+		if exists {
+			updated = append(updated, alias)
+			if existing.Path == bookmark.Path {
+				applied.Created, applied.LastUsed, applied.UsedCount = existing.Created, existing.LastUsed, existing.UsedCount
+			}
+		} else {
+			added = append(added, alias)
+		}
+		if err := store.PutBookmark(alias, &applied); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to save bookmark '%s': %w", alias, err)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(updated)
+	sort.Strings(removed)
+	return added, updated, removed, nil
+}
+
+func printSyncReport(added, updated, removed []string, report *gitsync.Report) {
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	red := color.New(color.FgRed)
+
+	for _, alias := range added {
+		green.Printf("+ %s", alias)
+		fmt.Printf(" → %s\n", report.Bookmarks[alias].Path)
+	}
+	for _, alias := range updated {
+		yellow.Printf("~ %s", alias)
+		fmt.Printf(" → %s\n", report.Bookmarks[alias].Path)
+	}
+	for _, alias := range removed {
+		red.Printf("- %s\n", alias)
+	}
+	for _, conflict := range report.Conflicts {
+		yellow.Fprintf(os.Stderr, "⚠️  Conflict on '%s': kept %s, the remote had %s\n",
+			conflict.Alias, conflict.Ours.Path, conflict.Theirs.Path)
+	}
+
+	summary := "✓ Synced, nothing to push"
+	if report.Pushed {
+		summary = "✓ Synced and pushed"
+	}
+	if n := len(added) + len(updated) + len(removed); n > 0 {
+		summary += fmt.Sprintf(", %d local change(s)", n)
+	}
+	green.Println(summary)
+}
+
+func init() {
+	syncCmd.AddCommand(syncInitCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestApplySynced(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	store, _ := storage.NewStore()
+	store.SaveBookmark("api", "/src/api")
+	store.UpdateUsage("api")
+	store.SaveBookmark("old", "/src/old")
+
+	synced := map[string]*storage.Bookmark{
+		"api":  {Path: "/src/api", Tags: []string{"work"}},
+		"docs": {Path: "/src/README.md", File: true, Opener: "code", Tasks: map[string]string{"build": "make"}},
+	}
+	added, updated, removed, err := applySynced(store, synced)
+	if err != nil {
+		t.Fatalf("applySynced failed: %v", err)
+	}
+	if len(added) != 1 || len(updated) != 1 || len(removed) != 1 {
+		t.Errorf("Expected docs added, api updated and old removed, got %v %v %v", added, updated, removed)
+	}
+	if api, _ := store.GetBookmark("api"); api.UsedCount != 1 || len(api.Tags) != 1 {
+		t.Errorf("Expected api to keep its usage and gain the synced tags, got %+v", api)
+	}
+	if docs, _ := store.GetBookmark("docs"); !docs.File || docs.Opener != "code" || docs.Tasks["build"] != "make" || docs.LastUsed.IsZero() {
+		t.Errorf("Expected every synced field of docs to be applied, got %+v", docs)
+	}
+
+	store.AddSynonym("api", "web")
+	_, _, _, err = applySynced(store, map[string]*storage.Bookmark{"api": synced["api"], "web": {Path: "/src/web"}})
+	if !errors.Is(err, storage.ErrSynonym) {
+		t.Errorf("Expected the store's refusal to be returned, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/remote"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// treeNode is one directory in the bookmark tree. After compaction name may
+// span several path segments.
+type treeNode struct {
+	name     string
+	aliases  []string
+	missing  bool
+	children map[string]*treeNode
+}
+
+func newTreeNode(name string) *treeNode {
+	return &treeNode{name: name, children: make(map[string]*treeNode)}
+}
+
+// buildBookmarkTree arranges bookmarks by path and collapses directories
+// that hold no alias and only one subdirectory into their child. Remote
+// bookmarks get a root per host; with any of them the returned node is an
+// unnamed forest holding the local tree and each host tree.
+func buildBookmarkTree(matches []storage.FuzzyMatch) *treeNode {
+	local := newTreeNode(string(filepath.Separator))
+	hosts := make(map[string]*treeNode)
+	for _, match := range matches {
+		if !storage.IsRemote(match.Bookmark.Path) {
+			path := filepath.Clean(match.Bookmark.Path)
+			node := insertTreePath(local, strings.Split(path, string(filepath.Separator)))
+			node.aliases = append(node.aliases, match.Alias)
+			if storage.Missing(match.Bookmark.Path) {
+				node.missing = true
+			}
+			continue
+		}
+
+		// Remote paths are kept as written; an unparsable one becomes a
+		// root of its own
+		host, segments := match.Bookmark.Path, []string(nil)
+		if target, err := remote.Parse(match.Bookmark.Path); err == nil {
+			host, segments = remoteTreeHost(target), strings.Split(target.Path, "/")
+		}
+		if hosts[host] == nil {
+			hosts[host] = newTreeNode(host)
+		}
+		node := insertTreePath(hosts[host], segments)
+		node.aliases = append(node.aliases, match.Alias)
+	}
+
+	compactTree(local)
+	if len(hosts) == 0 {
+		return local
+	}
+	forest := newTreeNode("")
+	if len(local.aliases) > 0 || len(local.children) > 0 {
+		forest.children[local.name] = local
+	}
+	for name, host := range hosts {
+		compactTree(host)
+		forest.children[name] = host
+	}
+	return forest
+}
+
+// insertTreePath walks down from node along segments, creating missing
+// children, and returns the last node
+func insertTreePath(node *treeNode, segments []string) *treeNode {
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		child, ok := node.children[segment]
+		if !ok {
+			child = newTreeNode(segment)
+			node.children[segment] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// remoteTreeHost names the root of a host's tree as [user@]host[:port]
+func remoteTreeHost(target *remote.Target) string {
+	host := target.Host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	if target.Port != "" {
+		host += ":" + target.Port
+	}
+	if target.User != "" {
+		host = target.User + "@" + host
+	}
+	return host
+}
+
+func compactTree(node *treeNode) {
+	for key, child := range node.children {
+		for len(child.aliases) == 0 && len(child.children) == 1 {
+			for _, grandchild := range child.children {
+				grandchild.name = filepath.Join(child.name, grandchild.name)
+				child = grandchild
+			}
+		}
+		node.children[key] = child
+		compactTree(child)
+	}
+
+	// The root itself collapses into a lone child so the tree starts at the
+	// common prefix of all bookmarks
+	if node.name == string(filepath.Separator) && len(node.aliases) == 0 && len(node.children) == 1 {
+		for _, child := range node.children {
+			*node = *child
+			node.name = filepath.Join(string(filepath.Separator), child.name)
+		}
+	}
+}
+
+// countBookmarks counts the aliases at and below node
+func (n *treeNode) countBookmarks() int {
+	count := len(n.aliases)
+	for _, child := range n.children {
+		count += child.countBookmarks()
+	}
+	return count
+}
+
+func (n *treeNode) sortedChildren() []*treeNode {
+	children := make([]*treeNode, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+	return children
+}
+
+// renderTree prints the tree below root; maxDepth limits how many levels
+// below the top are expanded (0 means unlimited). An unnamed root is a
+// forest whose trees are printed one after another.
+func renderTree(w io.Writer, root *treeNode, maxDepth int) {
+	if root.name == "" {
+		for _, tree := range root.sortedChildren() {
+			renderTree(w, tree, maxDepth)
+		}
+		return
+	}
+	renderTreeLine(w, root, "")
+	renderTreeChildren(w, root, "", 1, maxDepth)
+}
+
+func renderTreeChildren(w io.Writer, node *treeNode, indent string, depth, maxDepth int) {
+	children := node.sortedChildren()
+	for i, child := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		renderTreeLine(w, child, indent+branch)
+		if len(child.children) == 0 {
+			continue
+		}
+		if maxDepth > 0 && depth >= maxDepth {
+			hidden := child.countBookmarks() - len(child.aliases)
+			color.New(color.FgHiBlack).Fprintf(w, "%s%s└── … %d more\n", indent, next, hidden)
+			continue
+		}
+		renderTreeChildren(w, child, indent+next, depth+1, maxDepth)
+	}
+}
+
+func renderTreeLine(w io.Writer, node *treeNode, prefix string) {
+	fmt.Fprint(w, prefix)
+	if len(node.aliases) == 0 {
+		fmt.Fprintln(w, node.name)
+		return
+	}
+
+	sort.Strings(node.aliases)
+	aliases := "[" + strings.Join(node.aliases, ", ") + "]"
+	if node.missing {
+		color.New(color.FgRed).Fprintf(w, "❌ %s %s (MISSING)\n", node.name, aliases)
+		return
+	}
+	fmt.Fprintf(w, "%s ", node.name)
+	color.New(color.FgGreen).Fprintln(w, aliases)
+}
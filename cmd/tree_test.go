@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// disableColor turns colored output off for the rest of the test
+func disableColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+}
+
+func TestBookmarkTree(t *testing.T) {
+	disableColor(t)
+	root := t.TempDir()
+
+	var matches []storage.FuzzyMatch
+	for alias, path := range map[string]string{
+		"api":  filepath.Join(root, "src", "co", "api"),
+		"v2":   filepath.Join(root, "src", "co", "api", "v2"),
+		"web":  filepath.Join(root, "src", "co", "web"),
+		"deep": filepath.Join(root, "src", "co", "web", "ui", "x"),
+	} {
+		matches = append(matches, storage.FuzzyMatch{Alias: alias, Bookmark: &storage.Bookmark{Path: path}})
+	}
+
+	tree := buildBookmarkTree(matches)
+	if tree.name != filepath.Join(root, "src", "co") {
+		t.Errorf("Expected tree to start at the common prefix, got %s", tree.name)
+	}
+
+	var out bytes.Buffer
+	renderTree(&out, tree, 0)
+	want := filepath.Join(root, "src", "co") + `
+├── ❌ api [api] (MISSING)
+│   └── ❌ v2 [v2] (MISSING)
+└── ❌ web [web] (MISSING)
+    └── ❌ ui/x [deep] (MISSING)
+`
+	if out.String() != want {
+		t.Errorf("Unexpected tree:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	renderTree(&out, tree, 1)
+	if !strings.Contains(out.String(), "… 1 more") || strings.Contains(out.String(), "v2") {
+		t.Errorf("Expected depth 1 to hide nested bookmarks, got:\n%s", out.String())
+	}
+
+	matches = append(matches,
+		storage.FuzzyMatch{Alias: "build", Bookmark: &storage.Bookmark{Path: "ssh://me@host:2222/srv/build"}},
+		storage.FuzzyMatch{Alias: "logs", Bookmark: &storage.Bookmark{Path: "ssh://me@host:2222/srv/logs"}},
+	)
+	out.Reset()
+	renderTree(&out, buildBookmarkTree(matches), 0)
+	want += `me@host:2222
+└── srv
+    ├── build [build]
+    └── logs [logs]
+`
+	if out.String() != want {
+		t.Errorf("Expected remote bookmarks under their host, got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,617 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/rethil/fast-nav/internal/storage"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// errUICancelled is returned from the UI loop when the user quits without saving
+var errUICancelled = errors.New("cancelled")
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Manage bookmarks in a full-screen terminal UI",
+	Long: `Open a keyboard-driven bookmark manager.
+
+Keys:
+  ↑/↓, j/k        Move the cursor
+  /               Filter by alias, path or tag (Enter keeps, Esc clears)
+  s / S           Cycle the sort field / reverse the sort order
+  space           Mark or unmark a bookmark
+  r               Rename the bookmark under the cursor
+  p               Re-point the bookmark to another directory
+  t               Edit tags (comma-separated)
+  d               Delete the marked bookmarks (or the one under the cursor)
+  q               Save changes and quit
+  Q, Ctrl-C       Quit without saving`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return fmt.Errorf("fn ui requires an interactive terminal")
+		}
+
+		store, err := storage.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to initialize storage: %w", err)
+		}
+
+		oldState, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to enable raw terminal mode: %w", err)
+		}
+
+		// All edits happen in memory and are written with a single save on 'q'
+		m := newUIModel(store)
+		err = store.Batch(func() error {
+			return runUI(m, os.Stdin, os.Stdout, fd)
+		})
+
+		term.Restore(fd, oldState)
+		fmt.Print("\x1b[?25h\x1b[H\x1b[2J")
+
+		if errors.Is(err, errUICancelled) {
+			fmt.Println("No changes saved.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := m.commitRenames(); err != nil {
+			return err
+		}
+
+		fmt.Println("✓ Bookmarks saved")
+		return nil
+	},
+}
+
+// runUI drives the model from terminal input until the user quits
+func runUI(m *uiModel, in io.Reader, out io.Writer, fd int) error {
+	reader := bufio.NewReader(in)
+	fmt.Fprint(out, "\x1b[?25l")
+
+	for !m.done {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		m.render(out, width, height)
+
+		key, err := readKey(reader)
+		if err != nil {
+			return err
+		}
+		m.handleKey(key)
+	}
+
+	if !m.save {
+		return errUICancelled
+	}
+	return nil
+}
+
+// Key names produced by readKey for non-printable input
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdown"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+)
+
+// readKey reads a single keypress, decoding the common escape sequences
+func readKey(r *bufio.Reader) (string, error) {
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+
+	switch ch {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 127, 8:
+		return keyBackspace, nil
+	case 3:
+		return keyCtrlC, nil
+	case 27:
+		if r.Buffered() == 0 {
+			return keyEscape, nil
+		}
+		next, _ := r.ReadByte()
+		if next != '[' && next != 'O' {
+			return keyEscape, nil
+		}
+		code, _ := r.ReadByte()
+		switch code {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case '5', '6':
+			r.ReadByte() // trailing '~'
+			if code == '5' {
+				return keyPageUp, nil
+			}
+			return keyPageDown, nil
+		}
+		return "", nil
+	}
+
+	return string(ch), nil
+}
+
+// uiSortFields are the sort orders the UI cycles through with 's'
+var uiSortFields = []string{"name", "path", "used", "last-used"}
+
+// uiEntry is a bookmark row as shown by the UI
+type uiEntry struct {
+	alias    string
+	bookmark *storage.Bookmark
+	missing  bool
+}
+
+// uiPrompt is a single-line text input shown at the bottom of the screen
+type uiPrompt struct {
+	label    string
+	value    string
+	onSubmit func(value string) error
+}
+
+// uiModel holds the UI state; it mutates the store in memory as keys are handled
+type uiModel struct {
+	store     *storage.Store
+	entries   []uiEntry
+	visible   []uiEntry
+	filter    string
+	filtering bool
+	sortField int
+	reverse   bool
+	cursor    int
+	offset    int
+	marked    map[string]bool
+	prompt    *uiPrompt
+	status    string
+	done      bool
+	save      bool
+	// renames are applied to the trust list once the edits are saved
+	renames [][2]string
+}
+
+func newUIModel(store *storage.Store) *uiModel {
+	m := &uiModel{
+		store:  store,
+		marked: make(map[string]bool),
+	}
+	m.reload()
+	return m
+}
+
+// reload rebuilds the rows from the store and reapplies filter and sort
+func (m *uiModel) reload() {
+	m.entries = m.entries[:0]
+	for alias, bookmark := range m.store.GetAllBookmarks() {
+		missing := storage.Missing(bookmark.Path)
+		m.entries = append(m.entries, uiEntry{alias: alias, bookmark: bookmark, missing: missing})
+	}
+	m.refresh()
+}
+
+// refresh reapplies the filter and sort order without touching the store
+func (m *uiModel) refresh() {
+	filter := strings.ToLower(m.filter)
+	m.visible = m.visible[:0]
+	for _, entry := range m.entries {
+		if filter == "" || entryMatches(entry, filter) {
+			m.visible = append(m.visible, entry)
+		}
+	}
+
+	field := uiSortFields[m.sortField]
+	sort.SliceStable(m.visible, func(i, j int) bool {
+		a, b := m.visible[i], m.visible[j]
+		if m.reverse {
+			a, b = b, a
+		}
+		switch field {
+		case "path":
+			return a.bookmark.Path < b.bookmark.Path
+		case "used":
+			return a.bookmark.UsedCount > b.bookmark.UsedCount
+		case "last-used":
+			return a.bookmark.LastUsed.After(b.bookmark.LastUsed)
+		}
+		return a.alias < b.alias
+	})
+
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func entryMatches(entry uiEntry, filter string) bool {
+	if strings.Contains(strings.ToLower(entry.alias), filter) ||
+		strings.Contains(strings.ToLower(entry.bookmark.Path), filter) {
+		return true
+	}
+	for _, tag := range entry.bookmark.Tags {
+		if strings.Contains(strings.ToLower(tag), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectAlias moves the cursor to the given alias if it is visible
+func (m *uiModel) selectAlias(alias string) {
+	for i, entry := range m.visible {
+		if entry.alias == alias {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// current returns the entry under the cursor, if any
+func (m *uiModel) current() (uiEntry, bool) {
+	if len(m.visible) == 0 {
+		return uiEntry{}, false
+	}
+	return m.visible[m.cursor], true
+}
+
+// handleKey applies a single keypress to the model
+func (m *uiModel) handleKey(key string) {
+	if key == keyCtrlC {
+		m.done = true
+		return
+	}
+
+	if m.prompt != nil {
+		m.handlePromptKey(key)
+		return
+	}
+
+	if m.filtering {
+		switch key {
+		case keyEnter:
+			m.filtering = false
+		case keyEscape:
+			m.filtering = false
+			m.filter = ""
+		case keyBackspace:
+			_, size := utf8.DecodeLastRuneInString(m.filter)
+			m.filter = m.filter[:len(m.filter)-size]
+		case keyUp, keyDown, keyPageUp, keyPageDown:
+			m.move(key)
+		default:
+			if len([]rune(key)) == 1 {
+				m.filter += key
+			}
+		}
+		m.refresh()
+		return
+	}
+
+	m.status = ""
+	switch key {
+	case keyUp, keyDown, keyPageUp, keyPageDown, "k", "j", "g", "G":
+		m.move(key)
+	case "/":
+		m.filtering = true
+	case keyEscape:
+		m.filter = ""
+		m.refresh()
+	case "s":
+		m.sortField = (m.sortField + 1) % len(uiSortFields)
+		m.refresh()
+	case "S":
+		m.reverse = !m.reverse
+		m.refresh()
+	case " ":
+		if entry, ok := m.current(); ok {
+			if m.marked[entry.alias] {
+				delete(m.marked, entry.alias)
+			} else {
+				m.marked[entry.alias] = true
+			}
+			m.move(keyDown)
+		}
+	case "r":
+		m.startRename()
+	case "p":
+		m.startRepoint()
+	case "t":
+		m.startTag()
+	case "d":
+		m.startDelete()
+	case "q":
+		m.done = true
+		m.save = true
+	case "Q":
+		m.done = true
+	}
+}
+
+func (m *uiModel) handlePromptKey(key string) {
+	switch key {
+	case keyEscape:
+		m.prompt = nil
+		m.status = "Cancelled"
+	case keyEnter:
+		prompt := m.prompt
+		m.prompt = nil
+		if err := prompt.onSubmit(prompt.value); err != nil {
+			m.status = "Error: " + err.Error()
+		}
+	case keyBackspace:
+		runes := []rune(m.prompt.value)
+		if len(runes) > 0 {
+			m.prompt.value = string(runes[:len(runes)-1])
+		}
+	default:
+		if len([]rune(key)) == 1 {
+			m.prompt.value += key
+		}
+	}
+}
+
+func (m *uiModel) move(key string) {
+	const page = 10
+	switch key {
+	case keyUp, "k":
+		m.cursor--
+	case keyDown, "j":
+		m.cursor++
+	case keyPageUp:
+		m.cursor -= page
+	case keyPageDown:
+		m.cursor += page
+	case "g":
+		m.cursor = 0
+	case "G":
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *uiModel) startRename() {
+	entry, ok := m.current()
+	if !ok {
+		return
+	}
+	m.prompt = &uiPrompt{
+		label: fmt.Sprintf("Rename '%s' to", entry.alias),
+		value: entry.alias,
+		onSubmit: func(value string) error {
+			value = strings.TrimSpace(value)
+			if value == entry.alias {
+				return nil
+			}
+			if !isValidAlias(value) {
+				return fmt.Errorf("invalid alias: use only alphanumeric characters, dash, and underscore")
+			}
+			if err := m.store.RenameBookmark(entry.alias, value); err != nil {
+				return err
+			}
+			m.renames = append(m.renames, [2]string{entry.alias, value})
+			if m.marked[entry.alias] {
+				delete(m.marked, entry.alias)
+				m.marked[value] = true
+			}
+			m.status = fmt.Sprintf("Renamed '%s' → '%s'", entry.alias, value)
+			m.reload()
+			m.selectAlias(value)
+			return nil
+		},
+	}
+}
+
+// commitRenames moves the allowed hooks of every bookmark renamed in the UI,
+// in order, once the store has been saved
+func (m *uiModel) commitRenames() error {
+	for _, rename := range m.renames {
+		if err := moveTrust(rename[0], rename[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *uiModel) startRepoint() {
+	entry, ok := m.current()
+	if !ok {
+		return
+	}
+	m.prompt = &uiPrompt{
+		label: fmt.Sprintf("New path for '%s'", entry.alias),
+		value: entry.bookmark.Path,
+		onSubmit: func(value string) error {
+			path, err := homedir.Expand(strings.TrimSpace(value))
+			if err != nil {
+				return err
+			}
+			path, err = filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if entry.bookmark.File {
+				if !isFile(path) {
+					return fmt.Errorf("not a file: %s", path)
+				}
+			} else if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				return fmt.Errorf("not a directory: %s", path)
+			}
+			if err := m.store.SetPath(entry.alias, path); err != nil {
+				return err
+			}
+			m.status = fmt.Sprintf("Updated '%s' → %s", entry.alias, path)
+			m.reload()
+			return nil
+		},
+	}
+}
+
+func (m *uiModel) startTag() {
+	entry, ok := m.current()
+	if !ok {
+		return
+	}
+	m.prompt = &uiPrompt{
+		label: fmt.Sprintf("Tags for '%s'", entry.alias),
+		value: strings.Join(entry.bookmark.Tags, ", "),
+		onSubmit: func(value string) error {
+			if err := m.store.SetTags(entry.alias, strings.Split(value, ",")); err != nil {
+				return err
+			}
+			m.status = fmt.Sprintf("Tagged '%s'", entry.alias)
+			m.reload()
+			return nil
+		},
+	}
+}
+
+func (m *uiModel) startDelete() {
+	var targets []string
+	for alias := range m.marked {
+		targets = append(targets, alias)
+	}
+	if len(targets) == 0 {
+		entry, ok := m.current()
+		if !ok {
+			return
+		}
+		targets = []string{entry.alias}
+	}
+	sort.Strings(targets)
+
+	m.prompt = &uiPrompt{
+		label: fmt.Sprintf("Delete %s? (y/N)", strings.Join(targets, ", ")),
+		onSubmit: func(value string) error {
+			if !strings.EqualFold(strings.TrimSpace(value), "y") {
+				m.status = "Cancelled"
+				return nil
+			}
+			for _, alias := range targets {
+				if err := m.store.DeleteBookmark(alias); err != nil {
+					return err
+				}
+				delete(m.marked, alias)
+			}
+			m.status = fmt.Sprintf("Deleted %d bookmark(s)", len(targets))
+			m.reload()
+			return nil
+		},
+	}
+}
+
+// render draws the whole screen for the given terminal size
+func (m *uiModel) render(w io.Writer, width, height int) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+
+	order := "↓"
+	if m.reverse {
+		order = "↑"
+	}
+	header := fmt.Sprintf("fn — %d bookmark(s)  sort: %s %s  marked: %d",
+		len(m.entries), uiSortFields[m.sortField], order, len(m.marked))
+	b.WriteString("\x1b[1m" + truncate(header, width) + "\x1b[0m\r\n")
+
+	filterLine := "Filter: " + m.filter
+	if m.filtering {
+		filterLine += "█"
+	}
+	b.WriteString(truncate(filterLine, width) + "\r\n")
+
+	// Two header lines and two footer lines surround the rows
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+
+	if len(m.visible) == 0 {
+		b.WriteString("  (no bookmarks)\r\n")
+	}
+	for i := m.offset; i < len(m.visible) && i < m.offset+rows; i++ {
+		entry := m.visible[i]
+
+		pointer := "  "
+		if i == m.cursor {
+			pointer = "> "
+		}
+		mark := " "
+		if m.marked[entry.alias] {
+			mark = "*"
+		}
+		marker := "📍"
+		if entry.missing {
+			marker = "❌"
+		}
+
+		line := fmt.Sprintf("%s%s %s %-15s → %s (used %d times)",
+			pointer, mark, marker, entry.alias, entry.bookmark.Path, entry.bookmark.UsedCount)
+		if len(entry.bookmark.Tags) > 0 {
+			line += " [" + strings.Join(entry.bookmark.Tags, ", ") + "]"
+		}
+		line = truncate(line, width)
+
+		switch {
+		case i == m.cursor:
+			line = "\x1b[7m" + line + "\x1b[0m"
+		case entry.missing:
+			line = "\x1b[31m" + line + "\x1b[0m"
+		}
+		b.WriteString(line + "\r\n")
+	}
+
+	b.WriteString("\r\n")
+	switch {
+	case m.prompt != nil:
+		b.WriteString(truncate(m.prompt.label+": "+m.prompt.value+"█", width))
+	case m.status != "":
+		b.WriteString(truncate(m.status, width))
+	default:
+		b.WriteString(truncate("/ filter  s sort  space mark  r rename  p path  t tags  d delete  q save  Q quit", width))
+	}
+
+	io.WriteString(w, b.String())
+}
+
+// truncate shortens s to at most width runes
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width])
+}
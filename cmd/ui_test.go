@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestUIModel(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fn-ui-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, err := storage.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	for alias, path := range map[string]string{
+		"alpha": tempDir,
+		"beta":  "/definitely/does/not/exist",
+		"gamma": tempDir,
+	} {
+		if err := store.SaveBookmark(alias, path); err != nil {
+			t.Fatalf("Failed to save bookmark: %v", err)
+		}
+	}
+
+	typeText := func(m *uiModel, text string) {
+		for _, r := range text {
+			m.handleKey(string(r))
+		}
+	}
+
+	t.Run("FilterAndRender", func(t *testing.T) {
+		m := newUIModel(store)
+		m.handleKey("/")
+		typeText(m, "bet")
+		m.handleKey(keyEnter)
+
+		if len(m.visible) != 1 || m.visible[0].alias != "beta" {
+			t.Fatalf("Expected filter to leave only 'beta', got %v", m.visible)
+		}
+
+		var out bytes.Buffer
+		m.render(&out, 120, 20)
+		if !strings.Contains(out.String(), "❌") {
+			t.Errorf("Expected missing marker in render output, got: %s", out.String())
+		}
+	})
+
+	t.Run("FilterBackspaceTrimsRune", func(t *testing.T) {
+		m := newUIModel(store)
+		m.handleKey("/")
+		typeText(m, "bé")
+		m.handleKey(keyBackspace)
+		if m.filter != "b" {
+			t.Errorf("Expected backspace to remove a whole character, got %q", m.filter)
+		}
+	})
+
+	t.Run("ReverseSort", func(t *testing.T) {
+		m := newUIModel(store)
+		m.handleKey("S")
+		if len(m.visible) != 3 || m.visible[0].alias != "gamma" || m.visible[2].alias != "alpha" {
+			t.Errorf("Expected reverse name order, got %v", m.visible)
+		}
+	})
+
+	t.Run("RepointKeepsStats", func(t *testing.T) {
+		store.UpdateUsage("gamma")
+		m := newUIModel(store)
+		m.selectAlias("gamma")
+		m.handleKey("p")
+		m.prompt.value = os.TempDir()
+		m.handleKey(keyEnter)
+
+		gamma, _ := store.GetBookmark("gamma")
+		if gamma.Path != os.TempDir() || gamma.UsedCount != 1 {
+			t.Errorf("Expected gamma re-pointed with its usage kept, got %+v", gamma)
+		}
+		store.SetPath("gamma", tempDir)
+	})
+
+	t.Run("CancelDiscardsRenames", func(t *testing.T) {
+		trust, _ := storage.LoadTrust()
+		alpha, _ := store.GetBookmark("alpha")
+		trust.Allow("alpha", alpha)
+		defer trust.Deny("alpha")
+
+		cancelled, _ := storage.NewStore()
+		m := newUIModel(cancelled)
+		err := cancelled.Batch(func() error {
+			m.handleKey("r")
+			for range "alpha" {
+				m.handleKey(keyBackspace)
+			}
+			typeText(m, "renamed")
+			m.handleKey(keyEnter)
+
+			m.selectAlias("beta")
+			m.handleKey("d")
+			m.handleKey("y")
+			m.handleKey(keyEnter)
+
+			m.handleKey("Q")
+			if m.save {
+				t.Error("Expected 'Q' to finish without saving")
+			}
+			return errUICancelled
+		})
+		if err != errUICancelled {
+			t.Fatalf("Expected the batch to be cancelled, got %v", err)
+		}
+
+		reloaded, _ := storage.NewStore()
+		bookmarks := reloaded.GetAllBookmarks()
+		if bookmarks["alpha"] == nil || bookmarks["beta"] == nil || bookmarks["renamed"] != nil {
+			t.Errorf("Expected nothing to be saved after cancelling, got %v", sortedAliases(bookmarks))
+		}
+		trust, _ = storage.LoadTrust()
+		if !trust.Trusted("alpha", alpha) {
+			t.Error("Expected the allowed hooks to stay with 'alpha' after cancelling")
+		}
+	})
+
+	t.Run("EditsAreBatched", func(t *testing.T) {
+		err := store.Batch(func() error {
+			m := newUIModel(store)
+
+			// Rename the first row (alpha, sorted by name)
+			m.handleKey("r")
+			for range "alpha" {
+				m.handleKey(keyBackspace)
+			}
+			typeText(m, "first")
+			m.handleKey(keyEnter)
+
+			// Tag the row under the cursor
+			m.handleKey("t")
+			typeText(m, "work, go")
+			m.handleKey(keyEnter)
+
+			// Mark beta and gamma, then delete both
+			m.handleKey("g")
+			m.handleKey(" ")
+			m.handleKey("j")
+			m.handleKey(" ")
+			m.handleKey("d")
+			m.handleKey("y")
+			m.handleKey(keyEnter)
+
+			m.handleKey("q")
+			if !m.done || !m.save {
+				t.Error("Expected 'q' to finish with save")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Batch failed: %v", err)
+		}
+
+		reloaded, err := storage.NewStore()
+		if err != nil {
+			t.Fatalf("Failed to reload store: %v", err)
+		}
+		bookmarks := reloaded.GetAllBookmarks()
+		if len(bookmarks) != 1 {
+			t.Fatalf("Expected 1 bookmark after edits, got %d", len(bookmarks))
+		}
+		first, exists := bookmarks["first"]
+		if !exists {
+			t.Fatal("Expected 'alpha' to be renamed to 'first'")
+		}
+		if strings.Join(first.Tags, ",") != "go,work" {
+			t.Errorf("Expected tags go,work, got %v", first.Tags)
+		}
+	})
+}
@@ -0,0 +1,270 @@
+// Package exporter writes bookmarks in formats that can be shared and read
+// back with 'fn import'
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// Formats are the supported --format values
+var Formats = []string{"json", "csv", "toml", "yaml", "txt"}
+
+// Options control what is exported
+type Options struct {
+	// StripUsage leaves out created/used_count/last_used so that only the
+	// alias, path and tags are shared
+	StripUsage bool
+	// Synonyms maps extra names to the aliases they stand for, as returned
+	// by Store.GetSynonyms. Each is written with its bookmark.
+	Synonyms map[string]string
+}
+
+// FormatForFile guesses the format from a file name's extension, returning
+// "" if it is not recognised
+func FormatForFile(name string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	switch ext {
+	case "yml":
+		return "yaml"
+	case "json", "csv", "toml", "yaml", "txt":
+		return ext
+	}
+	return ""
+}
+
+// entry is a bookmark flattened for writing, sorted by alias
+type entry struct {
+	alias    string
+	bookmark *storage.Bookmark
+	synonyms []string
+}
+
+// Write encodes bookmarks in the given format
+func Write(w io.Writer, format string, bookmarks map[string]*storage.Bookmark, opts Options) error {
+	synonyms := make(map[string][]string)
+	for name, alias := range opts.Synonyms {
+		synonyms[alias] = append(synonyms[alias], name)
+	}
+	entries := make([]entry, 0, len(bookmarks))
+	for alias, bookmark := range bookmarks {
+		sort.Strings(synonyms[alias])
+		entries = append(entries, entry{alias: alias, bookmark: bookmark, synonyms: synonyms[alias]})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].alias < entries[j].alias })
+
+	switch format {
+	case "json":
+		return writeJSON(w, entries, opts)
+	case "csv":
+		return writeCSV(w, entries, opts)
+	case "toml":
+		return writeTOML(w, entries, opts)
+	case "yaml":
+		return writeYAML(w, entries, opts)
+	case "txt":
+		return writeTXT(w, entries)
+	}
+	return fmt.Errorf("unknown export format: %s (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// jsonBookmark mirrors storage.Bookmark with the usage fields optional, so
+// the JSON export reads like bookmarks.json
+type jsonBookmark struct {
+	Path      string            `json:"path"`
+	Tags      []string          `json:"tags,omitempty"`
+	File      bool              `json:"file,omitempty"`
+	Opener    string            `json:"opener,omitempty"`
+	Hooks     []string          `json:"hooks,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Tasks     map[string]string `json:"tasks,omitempty"`
+	Created   string            `json:"created,omitempty"`
+	UsedCount int               `json:"used_count,omitempty"`
+	LastUsed  string            `json:"last_used,omitempty"`
+}
+
+func writeJSON(w io.Writer, entries []entry, opts Options) error {
+	data := struct {
+		Version   string                   `json:"version"`
+		Bookmarks map[string]*jsonBookmark `json:"bookmarks"`
+		Synonyms  map[string]string        `json:"synonyms,omitempty"`
+	}{Version: "1.0", Bookmarks: make(map[string]*jsonBookmark)}
+
+	for _, e := range entries {
+		b := &jsonBookmark{
+			Path:   e.bookmark.Path,
+			Tags:   e.bookmark.Tags,
+			File:   e.bookmark.File,
+			Opener: e.bookmark.Opener,
+			Hooks:  e.bookmark.Hooks,
+			Env:    e.bookmark.Env,
+			Tasks:  e.bookmark.Tasks,
+		}
+		if !opts.StripUsage {
+			b.Created = formatTime(e.bookmark.Created)
+			b.UsedCount = e.bookmark.UsedCount
+			b.LastUsed = formatTime(e.bookmark.LastUsed)
+		}
+		data.Bookmarks[e.alias] = b
+		for _, synonym := range e.synonyms {
+			if data.Synonyms == nil {
+				data.Synonyms = make(map[string]string)
+			}
+			data.Synonyms[synonym] = e.alias
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(data)
+}
+
+func writeCSV(w io.Writer, entries []entry, opts Options) error {
+	writer := csv.NewWriter(w)
+	header := []string{"alias", "path", "tags", "file"}
+	if !opts.StripUsage {
+		header = append(header, "created", "used_count", "last_used")
+	}
+	writer.Write(header)
+
+	for _, e := range entries {
+		file := ""
+		if e.bookmark.File {
+			file = "true"
+		}
+		row := []string{e.alias, e.bookmark.Path, strings.Join(e.bookmark.Tags, ","), file}
+		if !opts.StripUsage {
+			row = append(row, formatTime(e.bookmark.Created), strconv.Itoa(e.bookmark.UsedCount), formatTime(e.bookmark.LastUsed))
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTOML(w io.Writer, entries []entry, opts Options) error {
+	var buf bytes.Buffer
+	buf.WriteString("version = \"1.0\"\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\n[bookmarks.%s]\n", quote(e.alias))
+		writeFields(&buf, "%s = %s\n", " = ", e, opts)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeYAML(w io.Writer, entries []entry, opts Options) error {
+	var buf bytes.Buffer
+	buf.WriteString("version: \"1.0\"\nbookmarks:")
+	if len(entries) == 0 {
+		buf.WriteString(" {}")
+	}
+	buf.WriteString("\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "  %s:\n", quote(e.alias))
+		writeFields(&buf, "    %s: %s\n", ": ", e, opts)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeFields writes the fields of a bookmark as key/value lines. Strings
+// are written as JSON strings, which TOML and YAML both accept as quoted
+// scalars. Maps are written inline, with sep between keys and values.
+func writeFields(buf *bytes.Buffer, layout, sep string, e entry, opts Options) {
+	bookmark := e.bookmark
+	fmt.Fprintf(buf, layout, "path", quote(bookmark.Path))
+	if len(bookmark.Tags) > 0 {
+		fmt.Fprintf(buf, layout, "tags", quoteList(bookmark.Tags))
+	}
+	if bookmark.File {
+		fmt.Fprintf(buf, layout, "file", "true")
+	}
+	if bookmark.Opener != "" {
+		fmt.Fprintf(buf, layout, "opener", quote(bookmark.Opener))
+	}
+	if len(bookmark.Hooks) > 0 {
+		fmt.Fprintf(buf, layout, "hooks", quoteList(bookmark.Hooks))
+	}
+	if len(bookmark.Env) > 0 {
+		fmt.Fprintf(buf, layout, "env", quoteMap(bookmark.Env, sep))
+	}
+	if len(bookmark.Tasks) > 0 {
+		fmt.Fprintf(buf, layout, "tasks", quoteMap(bookmark.Tasks, sep))
+	}
+	if len(e.synonyms) > 0 {
+		fmt.Fprintf(buf, layout, "synonyms", quoteList(e.synonyms))
+	}
+	if opts.StripUsage {
+		return
+	}
+	if created := formatTime(bookmark.Created); created != "" {
+		fmt.Fprintf(buf, layout, "created", created)
+	}
+	fmt.Fprintf(buf, layout, "used_count", strconv.Itoa(bookmark.UsedCount))
+	if lastUsed := formatTime(bookmark.LastUsed); lastUsed != "" {
+		fmt.Fprintf(buf, layout, "last_used", lastUsed)
+	}
+}
+
+// writeTXT writes "alias path" lines; only the alias and path survive
+func writeTXT(w io.Writer, entries []entry) error {
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%s %s\n", e.alias, e.bookmark.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func quote(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// quoteList writes strings as an array, which TOML and YAML read alike
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = quote(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// quoteMap writes a map as a TOML inline table or YAML flow mapping,
+// depending on sep, with its keys in order
+func quoteMap(m map[string]string, sep string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = quote(key) + sep + quote(m[key])
+	}
+	return "{ " + strings.Join(pairs, ", ") + " }"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/importer"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	lastUsed := time.Date(2024, 1, 20, 15, 45, 0, 0, time.UTC)
+	bookmarks := map[string]*storage.Bookmark{
+		"api":     {Path: "/src/api", Created: created, UsedCount: 42, LastUsed: lastUsed, Tags: []string{"go", "work"}, Hooks: []string{"nvm use", "echo \"a: b\"\nls"}},
+		"odd":     {Path: `/src/with "quotes", commas: and # marks`, Created: created, LastUsed: lastUsed, Env: map[string]string{"A": `x = "y", z: }`, "PROFILE": "prod"}},
+		"runbook": {Path: "/ops/runbook.md", Created: created, LastUsed: lastUsed, File: true, Opener: "code", Tasks: map[string]string{"lint": "mdl \"$@\""}},
+	}
+
+	synonyms := map[string]string{"backend": "api", "server": "api", "notes": "gone"}
+
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, bookmarks, Options{Synonyms: synonyms}); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			entries, err := importer.Parse(format, &buf)
+			if err != nil {
+				t.Fatalf("Parse failed: %v\n%s", err, buf.String())
+			}
+			if len(entries) != len(bookmarks) {
+				t.Fatalf("Expected %d entries, got %+v", len(bookmarks), entries)
+			}
+
+			for _, entry := range entries {
+				want := bookmarks[entry.Alias]
+				if want == nil || entry.Path != want.Path {
+					t.Errorf("Unexpected entry %+v", entry)
+					continue
+				}
+				if format == "txt" {
+					continue // plain text carries only alias and path
+				}
+				if strings.Join(entry.Tags, ",") != strings.Join(want.Tags, ",") {
+					t.Errorf("%s: expected tags %v, got %v", entry.Alias, want.Tags, entry.Tags)
+				}
+				if entry.File != want.File {
+					t.Errorf("%s: expected file %v, got %v", entry.Alias, want.File, entry.File)
+				}
+				if entry.UseCount() != want.UsedCount || !entry.Created.Equal(want.Created) || !entry.LastUsed.Equal(want.LastUsed) {
+					t.Errorf("%s: usage not preserved: %+v", entry.Alias, entry)
+				}
+				if format == "csv" {
+					continue // CSV leaves out per-bookmark settings
+				}
+				if entry.Opener != want.Opener {
+					t.Errorf("%s: expected opener %q, got %q", entry.Alias, want.Opener, entry.Opener)
+				}
+				if !reflect.DeepEqual(entry.Hooks, want.Hooks) {
+					t.Errorf("%s: expected hooks %q, got %q", entry.Alias, want.Hooks, entry.Hooks)
+				}
+				if !reflect.DeepEqual(entry.Env, want.Env) {
+					t.Errorf("%s: expected env %q, got %q", entry.Alias, want.Env, entry.Env)
+				}
+				if !reflect.DeepEqual(entry.Tasks, want.Tasks) {
+					t.Errorf("%s: expected tasks %q, got %q", entry.Alias, want.Tasks, entry.Tasks)
+				}
+				wantSynonyms := map[string][]string{"api": {"backend", "server"}}[entry.Alias]
+				if !reflect.DeepEqual(entry.Synonyms, wantSynonyms) {
+					t.Errorf("%s: expected synonyms %q, got %q", entry.Alias, wantSynonyms, entry.Synonyms)
+				}
+			}
+		})
+	}
+}
+
+func TestStripUsage(t *testing.T) {
+	bookmarks := map[string]*storage.Bookmark{
+		"api": {Path: "/src/api", Created: time.Now(), UsedCount: 42, LastUsed: time.Now()},
+	}
+
+	for _, format := range []string{"json", "csv", "toml", "yaml"} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, bookmarks, Options{StripUsage: true}); err != nil {
+			t.Fatalf("%s: Write failed: %v", format, err)
+		}
+		if strings.Contains(buf.String(), "used_count") || strings.Contains(buf.String(), "42") {
+			t.Errorf("%s: expected usage to be stripped, got:\n%s", format, buf.String())
+		}
+	}
+}
+
+func TestFormatForFile(t *testing.T) {
+	tests := map[string]string{"team.yml": "yaml", "b.TOML": "toml", "out.csv": "csv", "bookmarks": "", "": ""}
+	for name, want := range tests {
+		if got := FormatForFile(name); got != want {
+			t.Errorf("FormatForFile(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package gitsync shares bookmarks between machines through a git
+// repository. Aliases, synonyms, paths, tags, openers, hooks, variables and
+// tasks are committed; usage statistics stay on each machine, and so does
+// permission to apply hooks, variables and tasks.
+package gitsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rethil/fast-nav/internal/exporter"
+	"github.com/rethil/fast-nav/internal/importer"
+	"github.com/rethil/fast-nav/internal/merge"
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// fileName is the file committed to the sync repository
+const fileName = "bookmarks.json"
+
+// ErrNotInitialized is returned when sync has not been set up yet
+var ErrNotInitialized = errors.New("sync is not set up; run 'fn sync init <git-remote>' first")
+
+// Repo is the local clone that bookmarks are synced through
+type Repo struct {
+	dir string
+}
+
+// Dir returns where the sync clone lives (~/.fn/sync)
+func Dir() (string, error) {
+	configDir, err := storage.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "sync"), nil
+}
+
+// Open returns the sync clone in dir
+func Open(dir string) (*Repo, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil, ErrNotInitialized
+	}
+	return &Repo{dir: dir}, nil
+}
+
+// Init sets up dir to sync with remote. The remote may be empty, e.g. a
+// freshly created bare repository. Nothing is checked out: until the first
+// sync this machine has no common state with the others, so none of its
+// bookmarks count as deleted.
+func Init(dir, remote string) (*Repo, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("sync is already set up in %s", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	repo := &Repo{dir: dir}
+	if err := repo.init(remote); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return repo, nil
+}
+
+func (r *Repo) init(remote string) error {
+	if _, err := r.git("init", "--quiet"); err != nil {
+		return err
+	}
+	if _, err := r.git("remote", "add", "origin", remote); err != nil {
+		return err
+	}
+
+	// Follow the remote's default branch, if it has one yet
+	heads, err := r.git("ls-remote", "--symref", "origin", "HEAD")
+	if err != nil {
+		return err
+	}
+	if ref, ok := strings.CutPrefix(heads, "ref: "); ok {
+		ref, _, _ = strings.Cut(ref, "\t")
+		if _, err := r.git("symbolic-ref", "HEAD", ref); err != nil {
+			return err
+		}
+	}
+
+	// Commits need an identity; fall back to one for machines without it
+	if email, _ := r.git("config", "user.email"); email == "" {
+		host, _ := os.Hostname()
+		r.git("config", "user.name", "fn")
+		r.git("config", "user.email", "fn@"+host)
+	}
+	return nil
+}
+
+// Remote returns the URL of the repository being synced with
+func (r *Repo) Remote() string {
+	url, _ := r.git("remote", "get-url", "origin")
+	return url
+}
+
+// Report describes what a sync did
+type Report struct {
+	// Bookmarks is the merged set to apply locally, without usage data
+	Bookmarks map[string]*storage.Bookmark
+	// Synonyms is the merged set of synonyms to apply locally
+	Synonyms map[string]string
+	// Conflicts are aliases both sides re-pointed; the local path was kept
+	Conflicts []merge.Conflict
+	Pushed    bool
+}
+
+// Sync merges local bookmarks and synonyms with the last synced state and
+// whatever was pushed from other machines since, commits the result and
+// pushes it. apply is given the merged result to save locally before
+// anything is committed; if it fails, the sync stops there and the clone is
+// left as it was.
+func (r *Repo) Sync(local map[string]*storage.Bookmark, synonyms map[string]string, message string, apply func(*Report) error) (*Report, error) {
+	branch, err := r.git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := r.read("HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.git("fetch", "--quiet", "origin"); err != nil {
+		return nil, err
+	}
+	upstream := "refs/remotes/origin/" + branch
+	theirs := base
+	hasUpstream := r.hasRef(upstream)
+	if hasUpstream {
+		if theirs, err = r.read(upstream); err != nil {
+			return nil, err
+		}
+	}
+
+	result := merge.ThreeWay(base.Bookmarks, strip(local), theirs.Bookmarks)
+	for _, conflict := range result.Conflicts {
+		result.Bookmarks[conflict.Alias] = conflict.Ours
+	}
+	merged := merge.Synonyms(base.Synonyms, synonyms, theirs.Synonyms, result.Bookmarks)
+	report := &Report{Bookmarks: result.Bookmarks, Synonyms: merged, Conflicts: result.Conflicts}
+	if apply != nil {
+		if err := apply(report); err != nil {
+			return nil, err
+		}
+	}
+
+	// Build on top of what is already pushed, so history stays linear
+	if hasUpstream {
+		if _, err := r.git("reset", "--quiet", "--hard", upstream); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	err = exporter.Write(&buf, "json", result.Bookmarks, exporter.Options{StripUsage: true, Synonyms: merged})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, fileName), buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	if _, err := r.git("add", fileName); err != nil {
+		return nil, err
+	}
+	if _, err := r.git("diff", "--cached", "--quiet"); err != nil {
+		if _, err := r.git("commit", "--quiet", "-m", message); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.hasRef("HEAD") && (!hasUpstream || r.revision("HEAD") != r.revision(upstream)) {
+		if _, err := r.git("push", "--quiet", "origin", "HEAD:refs/heads/"+branch); err != nil {
+			return nil, fmt.Errorf("%w (another machine may have synced meanwhile; run 'fn sync' again)", err)
+		}
+		report.Pushed = true
+	}
+	return report, nil
+}
+
+// read parses the synced bookmarks and synonyms at a revision, returning an
+// empty set if the revision or file does not exist yet
+func (r *Repo) read(rev string) (*storage.BookmarkData, error) {
+	data := &storage.BookmarkData{
+		Bookmarks: make(map[string]*storage.Bookmark),
+		Synonyms:  make(map[string]string),
+	}
+	if !r.hasRef(rev + ":" + fileName) {
+		return data, nil
+	}
+
+	file, err := r.git("show", rev+":"+fileName)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := importer.Parse("json", strings.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s at %s: %v", storage.ErrCorruptStore, fileName, rev, err)
+	}
+	for _, entry := range entries {
+		data.Bookmarks[entry.Alias] = &storage.Bookmark{
+			Path:   entry.Path,
+			Tags:   entry.Tags,
+			File:   entry.File,
+			Opener: entry.Opener,
+			Hooks:  entry.Hooks,
+			Env:    entry.Env,
+			Tasks:  entry.Tasks,
+		}
+		for _, name := range entry.Synonyms {
+			data.Synonyms[name] = entry.Alias
+		}
+	}
+	return data, nil
+}
+
+func (r *Repo) hasRef(rev string) bool {
+	_, err := r.git("rev-parse", "--quiet", "--verify", rev)
+	return err == nil
+}
+
+func (r *Repo) revision(rev string) string {
+	hash, _ := r.git("rev-parse", rev)
+	return hash
+}
+
+func (r *Repo) git(args ...string) (string, error) {
+	return git(r.dir, args...)
+}
+
+// git runs a git command and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// strip drops per-machine usage data, leaving what is synced
+func strip(bookmarks map[string]*storage.Bookmark) map[string]*storage.Bookmark {
+	stripped := make(map[string]*storage.Bookmark, len(bookmarks))
+	for alias, bookmark := range bookmarks {
+		stripped[alias] = &storage.Bookmark{
+			Path:   bookmark.Path,
+			Tags:   bookmark.Tags,
+			File:   bookmark.File,
+			Opener: bookmark.Opener,
+			Hooks:  bookmark.Hooks,
+			Env:    bookmark.Env,
+			Tasks:  bookmark.Tasks,
+		}
+	}
+	return stripped
+}
@@ -0,0 +1,117 @@
+package gitsync
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestSyncBetweenMachines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	tempDir := t.TempDir()
+	remote := filepath.Join(tempDir, "remote.git")
+	if _, err := git("", "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatalf("Failed to create bare repository: %v", err)
+	}
+
+	laptop, err := Init(filepath.Join(tempDir, "laptop"), remote)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	laptopBookmarks := map[string]*storage.Bookmark{
+		"api":  {Path: "/src/api", UsedCount: 42, Tags: []string{"work"}, Hooks: []string{"nvm use"}, Env: map[string]string{"STAGE": "dev"}},
+		"docs": {Path: "/src/docs/README.md", File: true, Opener: "code", Tasks: map[string]string{"build": "make"}},
+	}
+	laptopSynonyms := map[string]string{"backend": "api"}
+	report, err := laptop.Sync(laptopBookmarks, laptopSynonyms, "laptop", nil)
+	if err != nil {
+		t.Fatalf("First sync failed: %v", err)
+	}
+	if !report.Pushed || len(report.Bookmarks) != 2 {
+		t.Fatalf("Expected both bookmarks to be pushed, got %+v", report)
+	}
+
+	// A second machine keeps its own bookmarks and gains the laptop's
+	desktop, err := Init(filepath.Join(tempDir, "desktop"), remote)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	report, err = desktop.Sync(map[string]*storage.Bookmark{"home": {Path: "/home/me"}}, nil, "desktop", nil)
+	if err != nil {
+		t.Fatalf("Desktop sync failed: %v", err)
+	}
+	if len(report.Bookmarks) != 3 || report.Bookmarks["api"].Tags[0] != "work" {
+		t.Fatalf("Expected the merged set of 3 bookmarks, got %v", report.Bookmarks)
+	}
+	if docs := report.Bookmarks["docs"]; !docs.File || docs.Opener != "code" || docs.Tasks["build"] != "make" {
+		t.Errorf("Expected file bookmarks to keep their kind, opener and tasks, got %+v", docs)
+	}
+	if hooks := report.Bookmarks["api"].Hooks; len(hooks) != 1 || hooks[0] != "nvm use" {
+		t.Errorf("Expected hooks to be synced, got %q", hooks)
+	}
+	if env := report.Bookmarks["api"].Env; env["STAGE"] != "dev" {
+		t.Errorf("Expected variables to be synced, got %q", env)
+	}
+	if report.Synonyms["backend"] != "api" {
+		t.Errorf("Expected synonyms to be synced, got %v", report.Synonyms)
+	}
+	if report.Bookmarks["api"].UsedCount != 0 {
+		t.Error("Usage counts should not be synced")
+	}
+
+	// The laptop deletes docs while the desktop re-points api
+	desktopBookmarks := report.Bookmarks
+	desktopBookmarks["api"] = &storage.Bookmark{Path: "/src/api-v2", Tags: []string{"work"}}
+	if _, err := desktop.Sync(desktopBookmarks, report.Synonyms, "desktop", nil); err != nil {
+		t.Fatalf("Desktop sync failed: %v", err)
+	}
+
+	delete(laptopBookmarks, "docs")
+	report, err = laptop.Sync(laptopBookmarks, laptopSynonyms, "laptop", nil)
+	if err != nil {
+		t.Fatalf("Laptop sync failed: %v", err)
+	}
+	if report.Bookmarks["docs"] != nil || report.Bookmarks["home"] == nil {
+		t.Errorf("Expected docs deleted and home added, got %v", report.Bookmarks)
+	}
+	if report.Bookmarks["api"] == nil || report.Bookmarks["api"].Path != "/src/api-v2" {
+		t.Errorf("Expected the desktop's edit of api, got %+v", report.Bookmarks["api"])
+	}
+	if len(report.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", report.Conflicts)
+	}
+
+	// Nothing changed since, so a further sync has nothing to push
+	report, err = laptop.Sync(report.Bookmarks, report.Synonyms, "laptop", nil)
+	if err != nil {
+		t.Fatalf("Laptop sync failed: %v", err)
+	}
+	if report.Pushed {
+		t.Error("Expected nothing to push")
+	}
+
+	// A failure to apply the result locally stops the sync before commit
+	head := laptop.revision("HEAD")
+	failed := errors.New("disk full")
+	laptopBookmarks["new"] = &storage.Bookmark{Path: "/src/new"}
+	_, err = laptop.Sync(laptopBookmarks, nil, "laptop", func(*Report) error { return failed })
+	if !errors.Is(err, failed) {
+		t.Fatalf("Expected the apply error, got %v", err)
+	}
+	if laptop.revision("HEAD") != head {
+		t.Error("Expected nothing to be committed when apply fails")
+	}
+}
+
+func TestOpenWithoutInit(t *testing.T) {
+	if _, err := Open(t.TempDir()); err != ErrNotInitialized {
+		t.Errorf("Expected ErrNotInitialized, got %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parsers for the formats written by 'fn export'. TOML and YAML are read
+// only as far as the exporter writes them: one table or mapping per
+// bookmark, holding JSON-quoted strings, string arrays, integers and
+// RFC 3339 timestamps.
+
+func isExportFormat(source string) bool {
+	switch source {
+	case "json", "csv", "toml", "yaml", "txt":
+		return true
+	}
+	return false
+}
+
+// exportFields is one bookmark as a set of exported key/value fields
+type exportFields struct {
+	alias  string
+	fields map[string]string
+}
+
+func parseExportJSON(data []byte) ([]Entry, error) {
+	var export struct {
+		Bookmarks map[string]struct {
+			Path      string            `json:"path"`
+			Tags      []string          `json:"tags"`
+			File      bool              `json:"file"`
+			Opener    string            `json:"opener"`
+			Hooks     []string          `json:"hooks"`
+			Env       map[string]string `json:"env"`
+			Tasks     map[string]string `json:"tasks"`
+			Created   string            `json:"created"`
+			UsedCount int               `json:"used_count"`
+			LastUsed  string            `json:"last_used"`
+		} `json:"bookmarks"`
+		Synonyms map[string]string `json:"synonyms"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid JSON export: %w", err)
+	}
+
+	var entries []Entry
+	for alias, b := range export.Bookmarks {
+		entry := Entry{
+			Alias:  alias,
+			Path:   expandPath(b.Path),
+			Score:  float64(b.UsedCount),
+			Tags:   b.Tags,
+			File:   b.File,
+			Opener: b.Opener,
+			Hooks:  b.Hooks,
+			Env:    b.Env,
+			Tasks:  b.Tasks,
+		}
+		var err error
+		if entry.Created, err = parseExportTime(b.Created); err != nil {
+			return nil, fmt.Errorf("bookmark %s: %w", alias, err)
+		}
+		if entry.LastUsed, err = parseExportTime(b.LastUsed); err != nil {
+			return nil, fmt.Errorf("bookmark %s: %w", alias, err)
+		}
+		for synonym, target := range export.Synonyms {
+			if target == alias {
+				entry.Synonyms = append(entry.Synonyms, synonym)
+			}
+		}
+		sort.Strings(entry.Synonyms)
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func parseExportCSV(data []byte) ([]Entry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV export: %w", err)
+	}
+
+	var records []exportFields
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV export: %w", err)
+		}
+
+		record := exportFields{fields: make(map[string]string)}
+		for i, name := range header {
+			if i >= len(row) {
+				break
+			}
+			if name == "alias" {
+				record.alias = row[i]
+			} else {
+				record.fields[name] = row[i]
+			}
+		}
+		// Tags are a comma-separated list rather than a JSON array here
+		if tags := record.fields["tags"]; tags != "" {
+			quoted, _ := json.Marshal(strings.Split(tags, ","))
+			record.fields["tags"] = string(quoted)
+		} else {
+			delete(record.fields, "tags")
+		}
+		if path, ok := record.fields["path"]; ok {
+			quoted, _ := json.Marshal(path)
+			record.fields["path"] = string(quoted)
+		}
+		records = append(records, record)
+	}
+	return entriesFromFields(records)
+}
+
+func parseExportTOML(data []byte) ([]Entry, error) {
+	var records []exportFields
+	err := eachLine(data, func(line string) error {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasPrefix(line, "[bookmarks.") || !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("unexpected table %s", line)
+			}
+			alias, err := unquoteKey(strings.TrimSuffix(strings.TrimPrefix(line, "[bookmarks."), "]"))
+			if err != nil {
+				return err
+			}
+			records = append(records, exportFields{alias: alias, fields: make(map[string]string)})
+			return nil
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("expected key = value, got %q", line)
+		}
+		if len(records) == 0 {
+			return nil // top-level keys such as version
+		}
+		records[len(records)-1].fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entriesFromFields(records)
+}
+
+func parseExportYAML(data []byte) ([]Entry, error) {
+	var records []exportFields
+	err := eachLine(data, func(line string) error {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, ok := cutYAMLKey(strings.TrimSpace(line))
+		if !ok {
+			return fmt.Errorf("expected key: value, got %q", line)
+		}
+
+		switch {
+		case indent == 0:
+			return nil // version, bookmarks
+		case indent <= 2:
+			alias, err := unquoteKey(key)
+			if err != nil {
+				return err
+			}
+			records = append(records, exportFields{alias: alias, fields: make(map[string]string)})
+		case len(records) > 0:
+			records[len(records)-1].fields[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entriesFromFields(records)
+}
+
+// cutYAMLKey splits "key: value", allowing the key to be a quoted string
+// that itself contains ": "
+func cutYAMLKey(line string) (key, value string, ok bool) {
+	if strings.HasPrefix(line, `"`) {
+		var quoted string
+		decoder := json.NewDecoder(strings.NewReader(line))
+		if err := decoder.Decode(&quoted); err != nil {
+			return "", "", false
+		}
+		rest := strings.TrimSpace(line[decoder.InputOffset():])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return line[:decoder.InputOffset()], strings.TrimSpace(rest[1:]), true
+	}
+	key, value, ok = strings.Cut(line, ":")
+	return strings.TrimSpace(key), strings.TrimSpace(value), ok
+}
+
+// parseExportTXT reads "alias path" lines
+func parseExportTXT(data []byte) ([]Entry, error) {
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		alias, path, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			return fmt.Errorf("expected 'alias path', got %q", line)
+		}
+		entries = append(entries, Entry{Alias: alias, Path: expandPath(strings.TrimSpace(path))})
+		return nil
+	})
+	return entries, err
+}
+
+// entriesFromFields decodes the shared TOML/YAML/CSV field values
+func entriesFromFields(records []exportFields) ([]Entry, error) {
+	var entries []Entry
+	for _, record := range records {
+		entry := Entry{Alias: record.alias}
+		fail := func(err error) ([]Entry, error) {
+			return nil, fmt.Errorf("bookmark %s: %w", record.alias, err)
+		}
+
+		path, ok := record.fields["path"]
+		if !ok {
+			return fail(fmt.Errorf("missing path"))
+		}
+		if err := json.Unmarshal([]byte(path), &entry.Path); err != nil {
+			return fail(fmt.Errorf("invalid path %s", path))
+		}
+		entry.Path = expandPath(entry.Path)
+
+		if tags, ok := record.fields["tags"]; ok {
+			if err := json.Unmarshal([]byte(tags), &entry.Tags); err != nil {
+				return fail(fmt.Errorf("invalid tags %s", tags))
+			}
+		}
+		switch file := record.fields["file"]; file {
+		case "true":
+			entry.File = true
+		case "", "false":
+		default:
+			return fail(fmt.Errorf("invalid file %s", file))
+		}
+		if opener, ok := record.fields["opener"]; ok {
+			if err := json.Unmarshal([]byte(opener), &entry.Opener); err != nil {
+				return fail(fmt.Errorf("invalid opener %s", opener))
+			}
+		}
+		if hooks, ok := record.fields["hooks"]; ok {
+			if err := json.Unmarshal([]byte(hooks), &entry.Hooks); err != nil {
+				return fail(fmt.Errorf("invalid hooks %s", hooks))
+			}
+		}
+		if env, ok := record.fields["env"]; ok {
+			var err error
+			if entry.Env, err = parseInlineMap(env); err != nil {
+				return fail(fmt.Errorf("invalid env %s", env))
+			}
+		}
+		if tasks, ok := record.fields["tasks"]; ok {
+			var err error
+			if entry.Tasks, err = parseInlineMap(tasks); err != nil {
+				return fail(fmt.Errorf("invalid tasks %s", tasks))
+			}
+		}
+		if synonyms, ok := record.fields["synonyms"]; ok {
+			if err := json.Unmarshal([]byte(synonyms), &entry.Synonyms); err != nil {
+				return fail(fmt.Errorf("invalid synonyms %s", synonyms))
+			}
+		}
+		if count := record.fields["used_count"]; count != "" {
+			n, err := strconv.Atoi(count)
+			if err != nil {
+				return fail(fmt.Errorf("invalid used_count %s", count))
+			}
+			entry.Score = float64(n)
+		}
+		var err error
+		if entry.Created, err = parseExportTime(record.fields["created"]); err != nil {
+			return fail(err)
+		}
+		if entry.LastUsed, err = parseExportTime(record.fields["last_used"]); err != nil {
+			return fail(err)
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseInlineMap reads the TOML inline tables ({ "k" = "v" }) and YAML flow
+// mappings ({ "k": "v" }) the exporter writes, with quoted keys and values
+func parseInlineMap(value string) (map[string]string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(value), "{")
+	if !ok {
+		return nil, fmt.Errorf("expected {")
+	}
+
+	m := make(map[string]string)
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "}" {
+			return m, nil
+		}
+		key, after, err := cutQuoted(rest)
+		if err != nil {
+			return nil, err
+		}
+		after = strings.TrimSpace(after)
+		if after == "" || (after[0] != '=' && after[0] != ':') {
+			return nil, fmt.Errorf("expected = or : after %q", key)
+		}
+		m[key], after, err = cutQuoted(strings.TrimSpace(after[1:]))
+		if err != nil {
+			return nil, err
+		}
+		rest = strings.TrimSpace(after)
+		if next, ok := strings.CutPrefix(rest, ","); ok {
+			rest = next
+		} else if rest != "}" {
+			return nil, fmt.Errorf("expected , or }")
+		}
+	}
+}
+
+// cutQuoted decodes the JSON string at the start of s and returns the rest
+func cutQuoted(s string) (string, string, error) {
+	var value string
+	decoder := json.NewDecoder(strings.NewReader(s))
+	if err := decoder.Decode(&value); err != nil {
+		return "", "", err
+	}
+	return value, s[decoder.InputOffset():], nil
+}
+
+// sortEntries orders entries by alias, since JSON objects are unordered
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Alias < entries[j].Alias })
+}
+
+func unquoteKey(key string) (string, error) {
+	if !strings.HasPrefix(key, `"`) {
+		return key, nil
+	}
+	var alias string
+	if err := json.Unmarshal([]byte(key), &alias); err != nil {
+		return "", fmt.Errorf("invalid key %s", key)
+	}
+	return alias, nil
+}
+
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s", value)
+	}
+	return t, nil
+}
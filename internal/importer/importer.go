@@ -0,0 +1,295 @@
+// Package importer reads the data files of other directory-jumping tools
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Entry is one directory found in another tool's data
+type Entry struct {
+	Alias    string            // empty for tools that only store paths
+	Path     string            // absolute, with ~ and $HOME expanded
+	Score    float64           // the tool's rank or weight, roughly a visit count
+	LastUsed time.Time         // zero if the tool does not record it
+	Created  time.Time         // only set by fn's own export formats
+	Tags     []string          // only set by fn's own export formats
+	File     bool              // Path is a file; only set by fn's own export formats
+	Opener   string            // only set by fn's own export formats
+	Hooks    []string          // only set by fn's own export formats
+	Env      map[string]string // only set by fn's own export formats
+	Tasks    map[string]string // only set by fn's own export formats
+	Synonyms []string          // other names for Alias; only set by fn's own export formats
+}
+
+// Sources are the supported --from values: other tools, then the formats
+// written by 'fn export'
+var Sources = []string{"zoxide", "autojump", "z", "fasd", "bashmarks", "shell-aliases", "json", "csv", "toml", "yaml", "txt"}
+
+// DefaultPath returns where a tool keeps its data by default
+func DefaultPath(source string) (string, error) {
+	paths := map[string]string{
+		"zoxide":        "~/.local/share/zoxide/db.zo",
+		"autojump":      "~/.local/share/autojump/autojump.txt",
+		"z":             "~/.z",
+		"fasd":          "~/.fasd",
+		"bashmarks":     "~/.sdirs",
+		"shell-aliases": "~/.bashrc",
+	}
+	path, ok := paths[source]
+	if !ok {
+		if isExportFormat(source) {
+			return "", fmt.Errorf("importing from %s requires a file", source)
+		}
+		return "", unknownSource(source)
+	}
+	return homedir.Expand(path)
+}
+
+// Parse reads entries in the given source format
+func Parse(source string, r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s data: %w", source, err)
+	}
+
+	switch source {
+	case "zoxide":
+		return parseZoxide(data)
+	case "autojump":
+		return parseAutojump(data)
+	case "z", "fasd":
+		return parseZ(data, source == "fasd")
+	case "bashmarks":
+		return parseBashmarks(data)
+	case "shell-aliases":
+		return parseShellAliases(data)
+	case "json":
+		return parseExportJSON(data)
+	case "csv":
+		return parseExportCSV(data)
+	case "toml":
+		return parseExportTOML(data)
+	case "yaml":
+		return parseExportYAML(data)
+	case "txt":
+		return parseExportTXT(data)
+	}
+	return nil, unknownSource(source)
+}
+
+func unknownSource(source string) error {
+	return fmt.Errorf("unknown import source: %s (want %s)", source, strings.Join(Sources, ", "))
+}
+
+// zoxideVersion is the database format written by zoxide 0.8 and later
+const zoxideVersion = 3
+
+// parseZoxide reads db.zo, a bincode-encoded list of (path, rank,
+// last_accessed). The text output of 'zoxide query --list --score' is
+// accepted too.
+func parseZoxide(data []byte) ([]Entry, error) {
+	if len(data) >= 4 && binary.LittleEndian.Uint32(data) == zoxideVersion {
+		return parseZoxideBinary(data[4:])
+	}
+
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		score, path, ok := strings.Cut(strings.TrimSpace(line), " ")
+		rank, err := strconv.ParseFloat(score, 64)
+		if !ok || err != nil {
+			return fmt.Errorf("expected '<score> <path>', got %q", line)
+		}
+		entries = append(entries, Entry{Path: expandPath(strings.TrimSpace(path)), Score: rank})
+		return nil
+	})
+	return entries, err
+}
+
+func parseZoxideBinary(data []byte) ([]Entry, error) {
+	r := bytes.NewReader(data)
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("invalid zoxide database: %w", err)
+	}
+
+	var entries []Entry
+	for i := uint64(0); i < count; i++ {
+		var length uint64
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return nil, fmt.Errorf("invalid zoxide database: %w", err)
+		}
+		if length > uint64(r.Len()) {
+			return nil, fmt.Errorf("invalid zoxide database: path length %d exceeds file size", length)
+		}
+		path := make([]byte, length)
+		io.ReadFull(r, path)
+
+		var record struct {
+			Rank         float64
+			LastAccessed uint64
+		}
+		if err := binary.Read(r, binary.LittleEndian, &record); err != nil {
+			return nil, fmt.Errorf("invalid zoxide database: %w", err)
+		}
+
+		entries = append(entries, Entry{
+			Path:     string(path),
+			Score:    record.Rank,
+			LastUsed: time.Unix(int64(record.LastAccessed), 0),
+		})
+	}
+	return entries, nil
+}
+
+// parseAutojump reads autojump.txt: "<weight>\t<path>" per line
+func parseAutojump(data []byte) ([]Entry, error) {
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		weight, path, ok := strings.Cut(line, "\t")
+		score, err := strconv.ParseFloat(weight, 64)
+		if !ok || err != nil {
+			return fmt.Errorf("expected '<weight>\\t<path>', got %q", line)
+		}
+		entries = append(entries, Entry{Path: expandPath(path), Score: score})
+		return nil
+	})
+	return entries, err
+}
+
+// parseZ reads the "<path>|<rank>|<unix time>" lines shared by z and fasd.
+// fasd also tracks files, which are dropped.
+func parseZ(data []byte, dirsOnly bool) ([]Entry, error) {
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		// Split from the right, since the path itself may contain '|'
+		fields := strings.Split(line, "|")
+		if len(fields) < 3 {
+			return fmt.Errorf("expected '<path>|<rank>|<time>', got %q", line)
+		}
+		n := len(fields)
+		path := strings.Join(fields[:n-2], "|")
+		rank, err := strconv.ParseFloat(fields[n-2], 64)
+		if err != nil {
+			return fmt.Errorf("invalid rank in %q", line)
+		}
+		seconds, err := strconv.ParseInt(fields[n-1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp in %q", line)
+		}
+
+		path = expandPath(path)
+		if dirsOnly {
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return nil
+			}
+		}
+
+		entries = append(entries, Entry{Path: path, Score: rank, LastUsed: time.Unix(seconds, 0)})
+		return nil
+	})
+	return entries, err
+}
+
+var bashmarksLine = regexp.MustCompile(`^export DIR_([A-Za-z0-9_]+)=(.*)$`)
+
+// parseBashmarks reads ~/.sdirs: export DIR_<name>="<path>" per line
+func parseBashmarks(data []byte) ([]Entry, error) {
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		m := bashmarksLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil // bashmarks files may hold other exports
+		}
+		entries = append(entries, Entry{Alias: m[1], Path: expandPath(unquote(m[2]))})
+		return nil
+	})
+	return entries, err
+}
+
+var cdAliasLine = regexp.MustCompile(`^\s*alias\s+([A-Za-z0-9_.-]+)=(?:'cd\s+(?:--\s+)?([^']+)'|"cd\s+(?:--\s+)?([^"]+)")\s*(?:#.*)?$`)
+
+// parseShellAliases picks alias x='cd /path' lines out of a shell rc file.
+// Aliases that do more than a plain cd are ignored.
+func parseShellAliases(data []byte) ([]Entry, error) {
+	var entries []Entry
+	err := eachLine(data, func(line string) error {
+		m := cdAliasLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil
+		}
+		path := strings.TrimSpace(m[2] + m[3])
+		if strings.ContainsAny(path, ";&|`") || strings.Contains(path, "$(") {
+			return nil
+		}
+		entries = append(entries, Entry{Alias: m[1], Path: expandPath(unquote(path))})
+		return nil
+	})
+	return entries, err
+}
+
+// eachLine calls fn for every non-empty, non-comment line, prefixing errors
+// with the line number
+func eachLine(data []byte, fn func(line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("line %d: %w", number, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// expandPath resolves ~, $HOME and ${HOME}, which rc files commonly use
+func expandPath(path string) string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return path
+	}
+	for _, prefix := range []string{"$HOME", "${HOME}"} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok && (rest == "" || rest[0] == '/') {
+			return home + rest
+		}
+	}
+	if expanded, err := homedir.Expand(path); err == nil {
+		return expanded
+	}
+	return path
+}
+
+// UseCount turns a tool's score into a usage count for the bookmark
+func (e Entry) UseCount() int {
+	if e.Score <= 0 || math.IsNaN(e.Score) {
+		return 0
+	}
+	if e.Score > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(math.Max(1, math.Round(e.Score)))
+}
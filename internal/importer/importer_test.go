@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func init() {
+	homedir.DisableCache = true
+}
+
+func TestParse(t *testing.T) {
+	home := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", originalHome)
+
+	file := filepath.Join(home, "notes.txt")
+	os.WriteFile(file, nil, 0644)
+
+	tests := []struct {
+		source string
+		input  string
+		want   []Entry
+	}{
+		{"zoxide", "  12.5 /src/api\n   1.0 /src/my web\n", []Entry{
+			{Path: "/src/api", Score: 12.5},
+			{Path: "/src/my web", Score: 1},
+		}},
+		{"autojump", "22.4\t/src/api\n10.0\t/src/a b\n", []Entry{
+			{Path: "/src/api", Score: 22.4},
+			{Path: "/src/a b", Score: 10},
+		}},
+		{"z", "/src/api|12|1700000000\n/src/a|b|3|1700000100\n", []Entry{
+			{Path: "/src/api", Score: 12, LastUsed: time.Unix(1700000000, 0)},
+			{Path: "/src/a|b", Score: 3, LastUsed: time.Unix(1700000100, 0)},
+		}},
+		{"fasd", "/src/api|2|1700000000\n" + file + "|5|1700000000\n~/notes.txt|1|1700000000\n", []Entry{
+			{Path: "/src/api", Score: 2, LastUsed: time.Unix(1700000000, 0)},
+		}},
+		{"bashmarks", "export DIR_api=\"/src/api\"\nexport DIR_web=$HOME/web\nexport EDITOR=vim\n", []Entry{
+			{Alias: "api", Path: "/src/api"},
+			{Alias: "web", Path: home + "/web"},
+		}},
+		{"shell-aliases", strings.Join([]string{
+			"# aliases",
+			"alias api='cd /src/api'",
+			`alias docs="cd ~/docs"  # docs`,
+			"alias ll='ls -l'",
+			"alias x='cd /tmp && ls'",
+			"  alias w='cd -- \"/src/w x\"'",
+		}, "\n"), []Entry{
+			{Alias: "api", Path: "/src/api"},
+			{Alias: "docs", Path: home + "/docs"},
+			{Alias: "w", Path: "/src/w x"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := Parse(tt.source, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %+v", len(tt.want), got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Parse("autojump", strings.NewReader("not a weight\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected a line-numbered parse error, got %v", err)
+	}
+	if _, err := Parse("fzf", strings.NewReader("")); err == nil {
+		t.Error("Expected error for unknown source")
+	}
+}
+
+func TestParseZoxideBinary(t *testing.T) {
+	var buf bytes.Buffer
+	write := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+
+	write(uint32(zoxideVersion))
+	write(uint64(2))
+	for _, dir := range []struct {
+		path string
+		rank float64
+		last uint64
+	}{{"/src/api", 8.5, 1700000000}, {"/src/web", 1, 1700000100}} {
+		write(uint64(len(dir.path)))
+		buf.WriteString(dir.path)
+		write(dir.rank)
+		write(dir.last)
+	}
+
+	entries, err := Parse("zoxide", &buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := []Entry{
+		{Path: "/src/api", Score: 8.5, LastUsed: time.Unix(1700000000, 0)},
+		{Path: "/src/web", Score: 1, LastUsed: time.Unix(1700000100, 0)},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Expected %+v, got %+v", want, entries)
+	}
+
+	truncated := append([]byte{}, 3, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0)
+	if _, err := Parse("zoxide", bytes.NewReader(truncated)); err == nil {
+		t.Error("Expected error for truncated database")
+	}
+}
+
+func TestUseCount(t *testing.T) {
+	tests := map[float64]int{0: 0, -1: 0, 0.2: 1, 2.5: 3, 41.4: 41}
+	for score, want := range tests {
+		if got := (Entry{Score: score}).UseCount(); got != want {
+			t.Errorf("UseCount(%v) = %d, want %d", score, got, want)
+		}
+	}
+}
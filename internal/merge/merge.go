@@ -0,0 +1,154 @@
+// Package merge reconciles diverged copies of the bookmark store alias by
+// alias
+package merge
+
+import (
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// Conflict is an alias that both sides point at different paths
+type Conflict struct {
+	Alias  string
+	Ours   *storage.Bookmark
+	Theirs *storage.Bookmark
+}
+
+// Result is the outcome of a merge. Conflicting aliases are left out of
+// Bookmarks for the caller to resolve.
+type Result struct {
+	Bookmarks map[string]*storage.Bookmark
+	Conflicts []Conflict // sorted by alias
+}
+
+// ThreeWay merges ours and theirs, using base (their last common state) to
+// tell which side changed an alias. A change on one side wins over an
+// unchanged other side, and an edit wins over a deletion. When both sides
+// keep an alias at the same path, their usage is combined, and so are their
+// tags if both changed them. base may be nil for a first merge, in which
+// case nothing counts as deleted.
+func ThreeWay(base, ours, theirs map[string]*storage.Bookmark) *Result {
+	result := &Result{Bookmarks: make(map[string]*storage.Bookmark)}
+
+	for _, alias := range aliases(base, ours, theirs) {
+		b, o, t := base[alias], ours[alias], theirs[alias]
+
+		switch {
+		case o == nil && t == nil:
+			// deleted on both sides, or never there
+		case o == nil:
+			if b == nil || !Equal(b, t) {
+				result.Bookmarks[alias] = t
+			}
+		case t == nil:
+			if b == nil || !Equal(b, o) {
+				result.Bookmarks[alias] = o
+			}
+		case b != nil && Equal(b, o):
+			result.Bookmarks[alias] = withUsage(t, o, b)
+		case b != nil && Equal(b, t):
+			result.Bookmarks[alias] = withUsage(o, t, b)
+		case o.Path == t.Path:
+			result.Bookmarks[alias] = withUsage(combine(o, t), t, b)
+		default:
+			result.Conflicts = append(result.Conflicts, Conflict{Alias: alias, Ours: o, Theirs: t})
+		}
+	}
+
+	return result
+}
+
+// Synonyms merges the synonym maps of both sides the way ThreeWay merges
+// bookmarks, with this side winning when both changed a synonym. Synonyms
+// whose bookmark is not in bookmarks, or whose name is an alias there, are
+// dropped.
+func Synonyms(base, ours, theirs map[string]string, bookmarks map[string]*storage.Bookmark) map[string]string {
+	merged := make(map[string]string)
+	for _, m := range []map[string]string{base, ours, theirs} {
+		for name := range m {
+			b, o, t := base[name], ours[name], theirs[name]
+			target := o
+			if o != t && t != b && (o == b || o == "") {
+				target = t
+			}
+			if target != "" && bookmarks[target] != nil && bookmarks[name] == nil {
+				merged[name] = target
+			}
+		}
+	}
+	return merged
+}
+
+// Equal reports whether two bookmarks have the same path, kind, tags and
+// settings. Usage statistics differ between machines and are ignored.
+func Equal(a, b *storage.Bookmark) bool {
+	if a.Path != b.Path || a.File != b.File || a.Opener != b.Opener || !slices.Equal(a.Hooks, b.Hooks) || !maps.Equal(a.Env, b.Env) || !maps.Equal(a.Tasks, b.Tasks) ||
+		len(a.Tags) != len(b.Tags) {
+		return false
+	}
+	for i := range a.Tags {
+		if a.Tags[i] != b.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withUsage returns chosen with the usage of other added, if both point at
+// the same path. Uses already counted in base are only counted once; the
+// latest use and the earliest creation time are kept.
+func withUsage(chosen, other, base *storage.Bookmark) *storage.Bookmark {
+	if chosen.Path != other.Path {
+		return chosen
+	}
+
+	merged := *chosen
+	merged.UsedCount = chosen.UsedCount + other.UsedCount
+	if base != nil && base.Path == chosen.Path {
+		merged.UsedCount -= base.UsedCount
+	}
+	if merged.UsedCount < chosen.UsedCount || merged.UsedCount < other.UsedCount {
+		merged.UsedCount = max(chosen.UsedCount, other.UsedCount)
+	}
+	if other.LastUsed.After(merged.LastUsed) {
+		merged.LastUsed = other.LastUsed
+	}
+	if !other.Created.IsZero() && (merged.Created.IsZero() || other.Created.Before(merged.Created)) {
+		merged.Created = other.Created
+	}
+	return &merged
+}
+
+// combine joins two bookmarks for the same path, keeping the tags of both
+func combine(ours, theirs *storage.Bookmark) *storage.Bookmark {
+	merged := *ours
+	seen := make(map[string]bool)
+	merged.Tags = nil
+	for _, tag := range append(append([]string{}, ours.Tags...), theirs.Tags...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	sort.Strings(merged.Tags)
+	return &merged
+}
+
+// aliases returns every alias in any of the maps, sorted
+func aliases(maps ...map[string]*storage.Bookmark) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, m := range maps {
+		for alias := range m {
+			if !seen[alias] {
+				seen[alias] = true
+				result = append(result, alias)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,135 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestThreeWay(t *testing.T) {
+	bm := func(path string, tags ...string) *storage.Bookmark {
+		return &storage.Bookmark{Path: path, Tags: tags}
+	}
+
+	base := map[string]*storage.Bookmark{
+		"same":        bm("/same"),
+		"ours-edit":   bm("/old"),
+		"theirs-edit": bm("/old"),
+		"ours-del":    bm("/del"),
+		"theirs-del":  bm("/del"),
+		"del-vs-edit": bm("/old"),
+		"both-del":    bm("/gone"),
+		"conflict":    bm("/old"),
+		"tags":        bm("/tags", "a"),
+	}
+	ours := map[string]*storage.Bookmark{
+		"same":        bm("/same"),
+		"ours-edit":   bm("/new"),
+		"theirs-edit": bm("/old"),
+		"theirs-del":  bm("/del"),
+		"conflict":    bm("/ours"),
+		"tags":        bm("/tags", "a", "b"),
+		"ours-new":    bm("/ours-new"),
+		"both-new":    bm("/both-new", "x"),
+	}
+	theirs := map[string]*storage.Bookmark{
+		"same":        bm("/same"),
+		"ours-edit":   bm("/old"),
+		"theirs-edit": bm("/new"),
+		"ours-del":    bm("/del"),
+		"del-vs-edit": bm("/edited"),
+		"conflict":    bm("/theirs"),
+		"tags":        bm("/tags", "a", "c"),
+		"theirs-new":  bm("/theirs-new"),
+		"both-new":    bm("/both-new", "y"),
+	}
+
+	result := ThreeWay(base, ours, theirs)
+
+	want := map[string]*storage.Bookmark{
+		"same":        bm("/same"),
+		"ours-edit":   bm("/new"),
+		"theirs-edit": bm("/new"),
+		"del-vs-edit": bm("/edited"),
+		"tags":        bm("/tags", "a", "b", "c"),
+		"ours-new":    bm("/ours-new"),
+		"theirs-new":  bm("/theirs-new"),
+		"both-new":    bm("/both-new", "x", "y"),
+	}
+	if !reflect.DeepEqual(result.Bookmarks, want) {
+		for alias, b := range result.Bookmarks {
+			t.Logf("%s: %+v", alias, *b)
+		}
+		t.Errorf("Unexpected merge result")
+	}
+
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Alias != "conflict" ||
+		result.Conflicts[0].Ours.Path != "/ours" || result.Conflicts[0].Theirs.Path != "/theirs" {
+		t.Errorf("Expected one conflict on 'conflict', got %+v", result.Conflicts)
+	}
+}
+
+func TestThreeWayWithoutBase(t *testing.T) {
+	ours := map[string]*storage.Bookmark{"a": {Path: "/a"}, "c": {Path: "/ours"}}
+	theirs := map[string]*storage.Bookmark{"b": {Path: "/b"}, "c": {Path: "/theirs"}}
+
+	result := ThreeWay(nil, ours, theirs)
+	if len(result.Bookmarks) != 2 || result.Bookmarks["a"] == nil || result.Bookmarks["b"] == nil {
+		t.Errorf("Expected both sides' bookmarks to be kept, got %v", result.Bookmarks)
+	}
+	if len(result.Conflicts) != 1 {
+		t.Errorf("Expected a conflict on c, got %+v", result.Conflicts)
+	}
+}
+
+func TestSynonyms(t *testing.T) {
+	bookmarks := map[string]*storage.Bookmark{"api": {Path: "/api"}, "web": {Path: "/web"}, "site": {Path: "/site"}}
+	base := map[string]string{"kept": "api", "gone": "api", "moved": "api", "both": "api"}
+	ours := map[string]string{"kept": "api", "moved": "api", "both": "site", "mine": "web", "site": "web"}
+	theirs := map[string]string{"kept": "api", "gone": "api", "moved": "web", "both": "web", "theirs": "web", "orphan": "nope"}
+
+	got := Synonyms(base, ours, theirs, bookmarks)
+	want := map[string]string{"kept": "api", "moved": "web", "both": "site", "mine": "web", "theirs": "web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Synonyms = %v, want %v", got, want)
+	}
+}
+
+func TestThreeWayUsage(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC) }
+
+	base := map[string]*storage.Bookmark{
+		"proj":  {Path: "/proj", UsedCount: 10, Created: day(1), LastUsed: day(2)},
+		"moved": {Path: "/old", UsedCount: 5, Created: day(1), LastUsed: day(2)},
+	}
+	ours := map[string]*storage.Bookmark{
+		"proj":  {Path: "/proj", UsedCount: 12, Created: day(1), LastUsed: day(5)},
+		"moved": {Path: "/old", UsedCount: 7, Created: day(1), LastUsed: day(3)},
+		"new":   {Path: "/new", UsedCount: 1, Created: day(4), LastUsed: day(4)},
+	}
+	theirs := map[string]*storage.Bookmark{
+		"proj":  {Path: "/proj", UsedCount: 15, Created: day(1), LastUsed: day(8)},
+		"moved": {Path: "/moved", UsedCount: 2, Created: day(6), LastUsed: day(6)},
+		"new":   {Path: "/new", UsedCount: 3, Created: day(3), LastUsed: day(3)},
+	}
+
+	result := ThreeWay(base, ours, theirs)
+
+	// Uses both sides made since base are counted once each
+	if proj := result.Bookmarks["proj"]; proj.UsedCount != 17 || !proj.LastUsed.Equal(day(8)) {
+		t.Errorf("Expected proj used 17 times, last on day 8, got %+v", proj)
+	}
+	// Without a shared base entry, counts are summed and the earliest creation kept
+	if created := result.Bookmarks["new"]; created.UsedCount != 4 || !created.Created.Equal(day(3)) || !created.LastUsed.Equal(day(4)) {
+		t.Errorf("Expected new used 4 times, created day 3, last used day 4, got %+v", created)
+	}
+	// A re-pointed bookmark does not inherit the old directory's usage
+	if moved := result.Bookmarks["moved"]; moved.Path != "/moved" || moved.UsedCount != 2 {
+		t.Errorf("Expected moved to take their bookmark as is, got %+v", moved)
+	}
+	if ours["proj"].UsedCount != 12 {
+		t.Error("ThreeWay should not modify its inputs")
+	}
+}
@@ -0,0 +1,134 @@
+// Package remote handles bookmarks that point at a directory on another
+// host, written as ssh://[user@]host[:port]/path
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+// Target is a parsed ssh:// bookmark path
+type Target struct {
+	User string // empty to use ssh's default
+	Host string
+	Port string // empty to use ssh's default
+	Path string // absolute, or starting with ~ for the remote home
+}
+
+// Parse reads an ssh://[user@]host[:port]/path URI. A path starting with
+// /~ is taken relative to the remote home directory, as in scp.
+func Parse(uri string) (*Target, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote target %s: %w", uri, err)
+	}
+	if u.Scheme != "ssh" {
+		return nil, fmt.Errorf("invalid remote target %s: expected ssh://[user@]host[:port]/path", uri)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid remote target %s: missing host", uri)
+	}
+	if u.Path == "" {
+		return nil, fmt.Errorf("invalid remote target %s: missing path", uri)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid remote target %s: unexpected query or fragment", uri)
+	}
+
+	target := &Target{Host: u.Hostname(), Port: u.Port(), Path: u.Path}
+	if u.User != nil {
+		target.User = u.User.Username()
+	}
+	if rest, ok := strings.CutPrefix(target.Path, "/~"); ok && (rest == "" || rest[0] == '/') {
+		target.Path = "~" + rest
+	}
+	return target, nil
+}
+
+// String returns the target as an ssh:// URI, escaping the path so that
+// Parse reads it back unchanged
+func (t *Target) String() string {
+	u := url.URL{Scheme: "ssh", Host: t.Host, Path: t.Path}
+	if strings.Contains(u.Host, ":") {
+		u.Host = "[" + u.Host + "]" // IPv6
+	}
+	if t.Port != "" {
+		u.Host += ":" + t.Port
+	}
+	if t.User != "" {
+		u.User = url.User(t.User)
+	}
+	if strings.HasPrefix(u.Path, "~") {
+		u.Path = "/" + u.Path
+	}
+	return u.String()
+}
+
+// sshArgs returns the ssh arguments that reach the host, before the command
+func (t *Target) sshArgs() []string {
+	var args []string
+	if t.Port != "" {
+		args = append(args, "-p", t.Port)
+	}
+	destination := t.Host
+	if t.User != "" {
+		destination = t.User + "@" + t.Host
+	}
+	// -- keeps a host name starting with - from being read as an option
+	return append(args, "--", destination)
+}
+
+// ShellArgs returns the ssh arguments for an interactive login shell in the
+// target directory
+func (t *Target) ShellArgs() []string {
+	args := append([]string{"-t"}, t.sshArgs()...)
+	return append(args, "cd "+t.quotedPath()+" && exec $SHELL -l")
+}
+
+// quotedPath quotes the path for the remote shell, leaving a leading ~ to
+// be expanded there
+func (t *Target) quotedPath() string {
+	path, home := t.Path, ""
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		home, path = "~", strings.TrimPrefix(rest, "/")
+		if path == "" {
+			return home
+		}
+		home += "/"
+	}
+	return home + "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
+
+// Open starts an interactive shell in the target directory, attached to
+// this terminal
+func (t *Target) Open() error {
+	cmd := exec.Command("ssh", t.ShellArgs()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Probe checks over ssh that the target directory exists. It returns
+// storage.ErrPathMissing if the host answered that it does not, and another
+// error if the host could not be asked.
+func (t *Target) Probe() error {
+	args := append([]string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=5"}, t.sshArgs()...)
+	args = append(args, "test -d "+t.quotedPath())
+	err := exec.Command("ssh", args...).Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return fmt.Errorf("%w: %s", storage.ErrPathMissing, t)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", t.Host, err)
+	}
+	return nil
+}
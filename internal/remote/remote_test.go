@@ -0,0 +1,76 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rethil/fast-nav/internal/storage"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want Target
+	}{
+		{"ssh://build1/srv/app", Target{Host: "build1", Path: "/srv/app"}},
+		{"ssh://me@build1:2222/srv/my%20app", Target{User: "me", Host: "build1", Port: "2222", Path: "/srv/my app"}},
+		{"ssh://build1/srv/100%25%23done", Target{Host: "build1", Path: "/srv/100%#done"}},
+		{"ssh://build1/~/src", Target{Host: "build1", Path: "~/src"}},
+		{"ssh://[::1]:22/tmp", Target{Host: "::1", Port: "22", Path: "/tmp"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.uri)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.uri, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.uri, *got, tt.want)
+		}
+		if got.String() != tt.uri {
+			t.Errorf("String() = %q, want %q", got.String(), tt.uri)
+		}
+	}
+
+	for _, uri := range []string{"/srv/app", "sftp://host/srv", "ssh:///srv", "ssh://host", "ssh://host/srv?x=1"} {
+		if _, err := Parse(uri); err == nil {
+			t.Errorf("Parse(%q) should fail", uri)
+		}
+	}
+}
+
+func TestShellArgs(t *testing.T) {
+	target := &Target{User: "me", Host: "build1", Port: "2222", Path: "/srv/it's here"}
+	want := []string{"-t", "-p", "2222", "--", "me@build1", `cd '/srv/it'\''s here' && exec $SHELL -l`}
+	if got := target.ShellArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ShellArgs() = %q, want %q", got, want)
+	}
+
+	home := &Target{Host: "build1", Path: "~/src"}
+	if got := home.ShellArgs(); got[len(got)-1] != `cd ~/'src' && exec $SHELL -l` {
+		t.Errorf("Expected ~ to be left unquoted, got %q", got[len(got)-1])
+	}
+}
+
+func TestProbe(t *testing.T) {
+	// A fake ssh that runs the remote command locally
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake ssh: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	if err := (&Target{Host: "localhost", Path: dir}).Probe(); err != nil {
+		t.Errorf("Expected existing directory to probe fine, got %v", err)
+	}
+
+	err := (&Target{Host: "localhost", Path: filepath.Join(dir, "gone")}).Probe()
+	if !errors.Is(err, storage.ErrPathMissing) {
+		t.Errorf("Expected ErrPathMissing, got %v", err)
+	}
+}
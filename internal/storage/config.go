@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DefaultOpener is used by 'fn open' when neither the command line, the
+// bookmark nor the config names an opener
+const DefaultOpener = "editor"
+
+// Config holds user settings from ~/.fn/config.json
+type Config struct {
+	// Openers maps opener names to shell commands, which are run with the
+	// path to open appended, as with $EDITOR
+	Openers map[string]string `json:"openers,omitempty"`
+
+	// DefaultOpener names the opener 'fn open' uses for bookmarks without
+	// one of their own
+	DefaultOpener string `json:"default_opener,omitempty"`
+}
+
+// builtinOpeners are available without any configuration. Entries in the
+// config file with the same name replace them.
+func builtinOpeners() map[string]string {
+	files := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		files = "open"
+	}
+	return map[string]string{
+		"editor": editorCommand(),
+		"files":  files,
+		"code":   "code",
+	}
+}
+
+// editorCommand returns the user's editor command line
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// LoadConfig reads ~/.fn/config.json. A missing file gives the defaults.
+func LoadConfig() (*Config, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(configDir, "config.json")
+
+	config := &Config{}
+	file, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(file, config)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, path, err)
+		}
+	}
+
+	openers := builtinOpeners()
+	for name, command := range config.Openers {
+		openers[name] = command
+	}
+	config.Openers = openers
+	if config.DefaultOpener == "" {
+		config.DefaultOpener = DefaultOpener
+	}
+	return config, nil
+}
+
+// Opener returns the command for the named opener
+func (c *Config) Opener(name string) (string, error) {
+	command, ok := c.Openers[name]
+	if !ok {
+		return "", fmt.Errorf("unknown opener: %s (want %s)", name, strings.Join(c.OpenerNames(), ", "))
+	}
+	return command, nil
+}
+
+// OpenerNames returns the names of all configured openers, sorted
+func (c *Config) OpenerNames() []string {
+	names := make([]string, 0, len(c.Openers))
+	for name := range c.Openers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed without a config file: %v", err)
+	}
+	if config.DefaultOpener != DefaultOpener {
+		t.Errorf("Expected default opener %s, got %s", DefaultOpener, config.DefaultOpener)
+	}
+	if editor, _ := config.Opener("editor"); editor != "nano" {
+		t.Errorf("Expected the editor opener to follow $EDITOR, got %q", editor)
+	}
+
+	os.MkdirAll(filepath.Join(tempDir, ".fn"), 0755)
+	configFile := filepath.Join(tempDir, ".fn", "config.json")
+	os.WriteFile(configFile, []byte(`{"default_opener": "idea", "openers": {"idea": "idea", "code": "code -n"}}`), 0644)
+
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.DefaultOpener != "idea" {
+		t.Errorf("Expected default opener idea, got %s", config.DefaultOpener)
+	}
+	if code, _ := config.Opener("code"); code != "code -n" {
+		t.Errorf("Expected the config to replace the code opener, got %q", code)
+	}
+	if _, err := config.Opener("files"); err != nil {
+		t.Errorf("Expected built-in openers to remain: %v", err)
+	}
+	if _, err := config.Opener("nope"); err == nil {
+		t.Error("Expected an error for an unknown opener")
+	}
+
+	os.WriteFile(configFile, []byte(`{"openers": [`), 0644)
+	if _, err := LoadConfig(); !errors.Is(err, ErrCorruptStore) {
+		t.Errorf("Expected ErrCorruptStore, got %v", err)
+	}
+}
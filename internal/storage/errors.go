@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Errors callers can test for with errors.Is. Messages are wrapped with
+// details such as the alias or path, so compare with errors.Is rather than ==.
+var (
+	// ErrNotFound means no bookmark matches the alias or pattern
+	ErrNotFound = errors.New("bookmark not found")
+
+	// ErrAmbiguous means a pattern matches more than one bookmark
+	ErrAmbiguous = errors.New("ambiguous match")
+
+	// ErrPathMissing means a bookmark points at a directory that no longer exists
+	ErrPathMissing = errors.New("directory no longer exists")
+
+	// ErrPermission is fs.ErrPermission, so any permission failure reported
+	// by the filesystem matches it
+	ErrPermission = fs.ErrPermission
+
+	// ErrCorruptStore means a data file under ~/.fn could not be parsed
+	ErrCorruptStore = errors.New("corrupt data file")
+
+	// ErrSynonym means a new alias is already a synonym of a bookmark
+	ErrSynonym = errors.New("name is already a synonym")
+)
+
+// AmbiguousError lists the bookmarks a pattern matched. It matches ErrAmbiguous.
+type AmbiguousError struct {
+	Pattern string
+	Matches []FuzzyMatch
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%s for '%s'", ErrAmbiguous, e.Pattern)
+}
+
+// Is reports whether target is ErrAmbiguous
+func (e *AmbiguousError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// CheckDir returns ErrPathMissing if path does not exist, or the underlying
+// error (e.g. ErrPermission) if it cannot be inspected. Remote targets are
+// not checked.
+func CheckDir(path string) error {
+	if IsRemote(path) {
+		return nil
+	}
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrPathMissing, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	return nil
+}
+
+// IsRemote reports whether a bookmark path is an ssh:// target rather than a
+// local directory
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "ssh://")
+}
+
+// Missing reports whether a local bookmark path no longer exists. Remote
+// targets are never reported missing, since checking them needs ssh.
+func Missing(path string) bool {
+	if IsRemote(path) {
+		return false
+	}
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxHistoryEntries caps the history file; the least recently visited
+// directories are dropped first
+const maxHistoryEntries = 5000
+
+// Visit records how often and when a directory was entered
+type Visit struct {
+	Count     int       `json:"count"`
+	LastVisit time.Time `json:"last_visit"`
+}
+
+type HistoryData struct {
+	Version string            `json:"version"`
+	Visits  map[string]*Visit `json:"visits"`
+	Ignored map[string]bool   `json:"ignored,omitempty"`
+}
+
+// DirScore is a visited directory ranked by frecency
+type DirScore struct {
+	Path  string
+	Visit *Visit
+	Score float64
+}
+
+// History is the directory-visit log fed by the shell cd hook. It is kept in
+// its own file so that recording visits never rewrites bookmarks.json.
+type History struct {
+	filePath string
+	data     *HistoryData
+}
+
+func NewHistory() (*History, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ensureDir(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &History{
+		filePath: filepath.Join(configDir, "history.json"),
+	}
+
+	err = history.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (h *History) load() error {
+	h.data = &HistoryData{
+		Version: "1.0",
+		Visits:  make(map[string]*Visit),
+		Ignored: make(map[string]bool),
+	}
+
+	file, err := os.ReadFile(h.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	err = json.Unmarshal(file, h.data)
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, h.filePath, err)
+	}
+
+	if h.data.Visits == nil {
+		h.data.Visits = make(map[string]*Visit)
+	}
+	if h.data.Ignored == nil {
+		h.data.Ignored = make(map[string]bool)
+	}
+
+	return nil
+}
+
+func (h *History) save() error {
+	data, err := json.MarshalIndent(h.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	err = os.WriteFile(h.filePath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+// RecordVisit counts a visit to dir
+func (h *History) RecordVisit(dir string) error {
+	dir = filepath.Clean(dir)
+
+	visit, exists := h.data.Visits[dir]
+	if !exists {
+		visit = &Visit{}
+		h.data.Visits[dir] = visit
+	}
+	visit.Count++
+	visit.LastVisit = time.Now()
+
+	h.prune()
+	return h.save()
+}
+
+// GetVisits returns all recorded directories keyed by path
+func (h *History) GetVisits() map[string]*Visit {
+	return h.data.Visits
+}
+
+// Ranked returns visited directories that are not ignored, highest frecency first
+func (h *History) Ranked() []DirScore {
+	now := time.Now()
+	var ranked []DirScore
+	for dir, visit := range h.data.Visits {
+		if h.data.Ignored[dir] {
+			continue
+		}
+		ranked = append(ranked, DirScore{
+			Path:  dir,
+			Visit: visit,
+			Score: Frecency(visit.Count, visit.LastVisit, now),
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+
+	return ranked
+}
+
+// Ignore excludes dir from suggestions permanently
+func (h *History) Ignore(dir string) error {
+	h.data.Ignored[filepath.Clean(dir)] = true
+	return h.save()
+}
+
+// IsIgnored reports whether dir was excluded with Ignore
+func (h *History) IsIgnored(dir string) bool {
+	return h.data.Ignored[filepath.Clean(dir)]
+}
+
+// Frecency weighs a use count by how recently the last use happened, so
+// places used often and lately rank above ones used often long ago
+func Frecency(count int, last, now time.Time) float64 {
+	age := now.Sub(last)
+	var weight float64
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 0.5
+	default:
+		weight = 0.25
+	}
+	return float64(count) * weight
+}
+
+// prune drops the least recently visited directories beyond maxHistoryEntries
+func (h *History) prune() {
+	if len(h.data.Visits) <= maxHistoryEntries {
+		return
+	}
+
+	dirs := make([]string, 0, len(h.data.Visits))
+	for dir := range h.data.Visits {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return h.data.Visits[dirs[i]].LastVisit.After(h.data.Visits[dirs[j]].LastVisit)
+	})
+
+	for _, dir := range dirs[maxHistoryEntries:] {
+		delete(h.data.Visits, dir)
+	}
+}
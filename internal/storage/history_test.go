@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordVisit(t *testing.T) {
+	setupTestStore(t)
+
+	history, err := NewHistory()
+	if err != nil {
+		t.Fatalf("NewHistory() failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err = history.RecordVisit("/tmp/project/")
+		if err != nil {
+			t.Fatalf("RecordVisit() failed: %v", err)
+		}
+	}
+
+	// Reload from disk to make sure visits are persisted
+	history, err = NewHistory()
+	if err != nil {
+		t.Fatalf("NewHistory() reload failed: %v", err)
+	}
+
+	visit, exists := history.GetVisits()["/tmp/project"]
+	if !exists {
+		t.Fatal("Expected visit to be recorded under the cleaned path")
+	}
+	if visit.Count != 3 {
+		t.Errorf("Expected 3 visits, got %d", visit.Count)
+	}
+	if visit.LastVisit.IsZero() {
+		t.Error("Expected LastVisit to be set")
+	}
+
+	// History lives next to, not inside, the bookmarks file
+	configDir, _ := ConfigDir()
+	if _, err := os.Stat(filepath.Join(configDir, "history.json")); err != nil {
+		t.Errorf("Expected history.json to exist: %v", err)
+	}
+}
+
+func TestFindByPath(t *testing.T) {
+	store := setupTestStore(t)
+
+	store.SaveBookmark("a", "/tmp/shared")
+	store.SaveBookmark("b", "/tmp/shared/")
+	store.SaveBookmark("c", "/tmp/other")
+
+	aliases := store.FindByPath("/tmp/shared")
+	if len(aliases) != 2 || aliases[0] != "a" || aliases[1] != "b" {
+		t.Errorf("Expected [a b], got %v", aliases)
+	}
+}
+
+func TestRankedAndIgnore(t *testing.T) {
+	setupTestStore(t)
+
+	history, err := NewHistory()
+	if err != nil {
+		t.Fatalf("NewHistory() failed: %v", err)
+	}
+
+	history.RecordVisit("/tmp/often")
+	history.RecordVisit("/tmp/often")
+	history.RecordVisit("/tmp/once")
+
+	// An old but heavily used directory ranks below recent ones
+	history.GetVisits()["/tmp/stale"] = &Visit{Count: 5, LastVisit: time.Now().Add(-30 * 24 * time.Hour)}
+
+	ranked := history.Ranked()
+	if len(ranked) != 3 {
+		t.Fatalf("Expected 3 ranked directories, got %d", len(ranked))
+	}
+	if ranked[0].Path != "/tmp/often" || ranked[1].Path != "/tmp/once" || ranked[2].Path != "/tmp/stale" {
+		t.Errorf("Unexpected ranking: %v, %v, %v", ranked[0].Path, ranked[1].Path, ranked[2].Path)
+	}
+
+	err = history.Ignore("/tmp/often/")
+	if err != nil {
+		t.Fatalf("Ignore() failed: %v", err)
+	}
+
+	history, _ = NewHistory()
+	if !history.IsIgnored("/tmp/often") {
+		t.Error("Expected ignore to be persisted")
+	}
+	for _, dir := range history.Ranked() {
+		if dir.Path == "/tmp/often" {
+			t.Error("Ignored directory should not be ranked")
+		}
+	}
+}
+
+func TestFrecency(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		count    int
+		age      time.Duration
+		expected float64
+	}{
+		{10, time.Minute, 40},
+		{10, 2 * time.Hour, 20},
+		{10, 3 * 24 * time.Hour, 5},
+		{10, 30 * 24 * time.Hour, 2.5},
+	}
+
+	for _, tt := range tests {
+		if got := Frecency(tt.count, now.Add(-tt.age), now); got != tt.expected {
+			t.Errorf("Frecency(%d, -%v) = %v, expected %v", tt.count, tt.age, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortKeys are the orders Query understands. name and path sort ascending;
+// the others put the most used, most recent or newest bookmarks first.
+var SortKeys = []string{"name", "path", "used", "last-used", "created", "frecency"}
+
+// Query selects and orders bookmarks. The zero value returns every bookmark
+// sorted by name.
+type Query struct {
+	Sort    string
+	Reverse bool
+
+	Missing   bool      // only bookmarks whose directory is gone
+	Existing  bool      // only bookmarks whose directory exists
+	Unused    bool      // only bookmarks never navigated to
+	UsedSince time.Time // only bookmarks used at or after this time
+	Under     string    // only bookmarks at or below this absolute directory
+	Tags      []string  // only bookmarks carrying at least one of these tags
+	Pattern   string    // only bookmarks whose alias or path contains this, ignoring case
+
+	Limit int // 0 means no limit
+}
+
+// Query returns the bookmarks selected by q, in q's order
+func (s *Store) Query(q Query) ([]FuzzyMatch, error) {
+	if q.Sort == "" {
+		q.Sort = "name"
+	}
+	if !validSortKey(q.Sort) {
+		return nil, fmt.Errorf("unknown sort key: %s (want %s)", q.Sort, strings.Join(SortKeys, ", "))
+	}
+
+	under := ""
+	if q.Under != "" {
+		under = filepath.Clean(q.Under)
+	}
+
+	pattern := strings.ToLower(q.Pattern)
+
+	var matches []FuzzyMatch
+	for alias, bookmark := range s.data.Bookmarks {
+		if q.Unused && bookmark.UsedCount > 0 {
+			continue
+		}
+		if !q.UsedSince.IsZero() && (bookmark.UsedCount == 0 || bookmark.LastUsed.Before(q.UsedSince)) {
+			continue
+		}
+		if under != "" && !isUnder(filepath.Clean(bookmark.Path), under) {
+			continue
+		}
+		if len(q.Tags) > 0 && !hasAnyTag(bookmark, q.Tags) {
+			continue
+		}
+		if pattern != "" && !strings.Contains(strings.ToLower(alias), pattern) &&
+			!strings.Contains(strings.ToLower(bookmark.Path), pattern) &&
+			!s.synonymContains(alias, pattern) {
+			continue
+		}
+		if q.Missing || q.Existing {
+			missing := Missing(bookmark.Path)
+			if (q.Missing && !missing) || (q.Existing && missing) {
+				continue
+			}
+		}
+
+		matches = append(matches, FuzzyMatch{
+			Alias:    alias,
+			Bookmark: bookmark,
+			Score:    bookmark.UsedCount,
+		})
+	}
+
+	SortMatches(matches, q.Sort, q.Reverse)
+
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	return matches, nil
+}
+
+// SortMatches orders matches by one of SortKeys, breaking ties by alias
+func SortMatches(matches []FuzzyMatch, key string, reverse bool) {
+	now := time.Now()
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		var cmp int
+		switch key {
+		case "path":
+			cmp = strings.Compare(a.Bookmark.Path, b.Bookmark.Path)
+		case "used":
+			cmp = b.Bookmark.UsedCount - a.Bookmark.UsedCount
+		case "last-used":
+			cmp = b.Bookmark.LastUsed.Compare(a.Bookmark.LastUsed)
+		case "created":
+			cmp = b.Bookmark.Created.Compare(a.Bookmark.Created)
+		case "frecency":
+			fa := Frecency(a.Bookmark.UsedCount, a.Bookmark.LastUsed, now)
+			fb := Frecency(b.Bookmark.UsedCount, b.Bookmark.LastUsed, now)
+			if fa > fb {
+				cmp = -1
+			} else if fa < fb {
+				cmp = 1
+			}
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(a.Alias, b.Alias)
+		}
+		if reverse {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+}
+
+func validSortKey(key string) bool {
+	for _, k := range SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(bookmark *Bookmark, tags []string) bool {
+	for _, tag := range bookmark.Tags {
+		for _, want := range tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isUnder reports whether path is dir or inside it
+func isUnder(path, dir string) bool {
+	if path == dir || dir == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// ParseAge parses a duration such as 90m, 12h, 7d or 2w
+func ParseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration: %s", value)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s (use e.g. 12h, 7d or 2w)", value)
+	}
+	return d, nil
+}
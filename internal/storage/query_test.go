@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	store := setupTestStore(t)
+	root := t.TempDir()
+	now := time.Now()
+
+	store.SaveBookmark("alpha", filepath.Join(root, "src", "alpha"))
+	store.SaveBookmark("beta", root)
+	store.SaveBookmark("gamma", filepath.Join(root, "src", "gamma"))
+	store.data.Bookmarks["alpha"].UsedCount = 5
+	store.data.Bookmarks["alpha"].LastUsed = now.Add(-30 * 24 * time.Hour)
+	store.data.Bookmarks["beta"].UsedCount = 2
+	store.data.Bookmarks["beta"].LastUsed = now.Add(-time.Minute)
+
+	aliases := func(q Query) []string {
+		t.Helper()
+		matches, err := store.Query(q)
+		if err != nil {
+			t.Fatalf("Query(%+v) failed: %v", q, err)
+		}
+		var result []string
+		for _, match := range matches {
+			result = append(result, match.Alias)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"DefaultByName", Query{}, []string{"alpha", "beta", "gamma"}},
+		{"UsedDescending", Query{Sort: "used"}, []string{"alpha", "beta", "gamma"}},
+		{"Reverse", Query{Sort: "name", Reverse: true}, []string{"gamma", "beta", "alpha"}},
+		{"Frecency", Query{Sort: "frecency"}, []string{"beta", "alpha", "gamma"}},
+		{"Unused", Query{Unused: true}, []string{"gamma"}},
+		{"UsedSince", Query{UsedSince: now.Add(-7 * 24 * time.Hour)}, []string{"beta"}},
+		{"Under", Query{Under: filepath.Join(root, "src")}, []string{"alpha", "gamma"}},
+		{"Existing", Query{Existing: true}, []string{"beta"}},
+		{"Missing", Query{Missing: true}, []string{"alpha", "gamma"}},
+		{"Limit", Query{Sort: "path", Limit: 1}, []string{"beta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aliases(tt.query)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	if _, err := store.Query(Query{Sort: "size"}); err == nil {
+		t.Error("Expected error for unknown sort key")
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for input, want := range tests {
+		got, err := ParseAge(input)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "d", "-3d", "soon"} {
+		if _, err := ParseAge(input); err == nil {
+			t.Errorf("Expected ParseAge(%q) to fail", input)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+const (
+	// maxSessionEntries caps a session's back/forward stack
+	maxSessionEntries = 100
+
+	// sessionExpiry is how long an untouched session file is kept
+	sessionExpiry = 30 * 24 * time.Hour
+)
+
+var validSessionID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// SessionStack is the navigation history of one shell session. Entries are
+// ordered oldest first and Index points at the current location.
+type SessionStack struct {
+	Entries   []string  `json:"entries"`
+	Index     int       `json:"index"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Session is a browser-style back/forward stack persisted per shell session
+type Session struct {
+	filePath string
+	data     *SessionStack
+}
+
+// OpenSession loads the stack for the given session id, creating it if needed
+func OpenSession(id string) (*Session, error) {
+	if !validSessionID.MatchString(id) {
+		return nil, fmt.Errorf("invalid session id: %q", id)
+	}
+
+	configDir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsDir := filepath.Join(configDir, "sessions")
+	err = ensureDir(sessionsDir)
+	if err != nil {
+		return nil, err
+	}
+	removeExpiredSessions(sessionsDir)
+
+	session := &Session{
+		filePath: filepath.Join(sessionsDir, id+".json"),
+		data:     &SessionStack{},
+	}
+
+	file, err := os.ReadFile(session.filePath)
+	if os.IsNotExist(err) {
+		return session, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	err = json.Unmarshal(file, session.data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, session.filePath, err)
+	}
+	// An empty stack has index 0; otherwise the index must name an entry
+	index, count := session.data.Index, len(session.data.Entries)
+	if index < 0 || (count > 0 && index >= count) || (count == 0 && index != 0) {
+		return nil, fmt.Errorf("%w: %s: index %d out of range for %d entries", ErrCorruptStore, session.filePath, index, count)
+	}
+
+	return session, nil
+}
+
+// removeExpiredSessions deletes stacks of shells that are long gone
+func removeExpiredSessions(dir string) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		info, err := file.Info()
+		if err == nil && time.Since(info.ModTime()) > sessionExpiry {
+			os.Remove(filepath.Join(dir, file.Name()))
+		}
+	}
+}
+
+func (s *Session) save() error {
+	s.data.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	err = os.WriteFile(s.filePath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns the stack, oldest first
+func (s *Session) Entries() []string {
+	return s.data.Entries
+}
+
+// Index returns the position of the current location in Entries
+func (s *Session) Index() int {
+	return s.data.Index
+}
+
+// Push records a jump from one directory to another, discarding any forward
+// history like a browser does
+func (s *Session) Push(from, to string) error {
+	s.visit(filepath.Clean(from))
+	s.visit(filepath.Clean(to))
+	return s.save()
+}
+
+// visit makes dir the current entry unless it already is
+func (s *Session) visit(dir string) {
+	if len(s.data.Entries) > 0 && s.data.Entries[s.data.Index] == dir {
+		return
+	}
+
+	if len(s.data.Entries) > 0 {
+		s.data.Entries = s.data.Entries[:s.data.Index+1]
+	}
+	s.data.Entries = append(s.data.Entries, dir)
+
+	if len(s.data.Entries) > maxSessionEntries {
+		s.data.Entries = s.data.Entries[len(s.data.Entries)-maxSessionEntries:]
+	}
+	s.data.Index = len(s.data.Entries) - 1
+}
+
+// Back moves n entries towards older locations and returns the new current
+// one. cwd is recorded first if the shell has moved away from the current
+// entry by other means, so that Forward can return to it.
+func (s *Session) Back(cwd string, n int) (string, error) {
+	s.visit(filepath.Clean(cwd))
+	if s.data.Index == 0 {
+		return "", fmt.Errorf("no earlier directory in this session")
+	}
+	return s.move(-n)
+}
+
+// Forward moves n entries towards newer locations and returns the new current one
+func (s *Session) Forward(n int) (string, error) {
+	if s.data.Index >= len(s.data.Entries)-1 {
+		return "", fmt.Errorf("no later directory in this session")
+	}
+	return s.move(n)
+}
+
+func (s *Session) move(delta int) (string, error) {
+	index := s.data.Index + delta
+	if index < 0 {
+		index = 0
+	}
+	if index > len(s.data.Entries)-1 {
+		index = len(s.data.Entries) - 1
+	}
+	s.data.Index = index
+	return s.data.Entries[index], s.save()
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionBackForward(t *testing.T) {
+	setupTestStore(t)
+
+	session, err := OpenSession("1234")
+	if err != nil {
+		t.Fatalf("OpenSession() failed: %v", err)
+	}
+
+	// home → a → b
+	if err := session.Push("/home", "/a"); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+	if err := session.Push("/a", "/b"); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+
+	path, err := session.Back("/b", 1)
+	if err != nil || path != "/a" {
+		t.Fatalf("Back(1) = %q, %v; expected /a", path, err)
+	}
+
+	// Stack is persisted per session
+	session, _ = OpenSession("1234")
+	path, err = session.Forward(1)
+	if err != nil || path != "/b" {
+		t.Fatalf("Forward(1) = %q, %v; expected /b", path, err)
+	}
+
+	if _, err := session.Forward(1); err == nil {
+		t.Error("Expected error when moving past the newest entry")
+	}
+
+	// Moving further back than possible stops at the oldest entry
+	path, err = session.Back("/b", 10)
+	if err != nil || path != "/home" {
+		t.Fatalf("Back(10) = %q, %v; expected /home", path, err)
+	}
+
+	// A new jump discards the forward history
+	if err := session.Push("/home", "/c"); err != nil {
+		t.Fatalf("Push() failed: %v", err)
+	}
+	entries := session.Entries()
+	if len(entries) != 2 || entries[0] != "/home" || entries[1] != "/c" {
+		t.Errorf("Expected [/home /c], got %v", entries)
+	}
+
+	// Other sessions are independent
+	other, _ := OpenSession("5678")
+	if len(other.Entries()) != 0 {
+		t.Errorf("Expected new session to be empty, got %v", other.Entries())
+	}
+}
+
+func TestSessionRecordsPlainCd(t *testing.T) {
+	setupTestStore(t)
+
+	session, _ := OpenSession("cd-test")
+	session.Push("/home", "/a")
+
+	// The user cd'd to /elsewhere by hand; back returns to /a and forward to /elsewhere
+	path, err := session.Back("/elsewhere", 1)
+	if err != nil || path != "/a" {
+		t.Fatalf("Back() = %q, %v; expected /a", path, err)
+	}
+	path, err = session.Forward(1)
+	if err != nil || path != "/elsewhere" {
+		t.Fatalf("Forward() = %q, %v; expected /elsewhere", path, err)
+	}
+}
+
+func TestOpenSessionRejectsInvalidID(t *testing.T) {
+	setupTestStore(t)
+
+	if _, err := OpenSession("../escape"); err == nil {
+		t.Error("Expected error for session id with path separators")
+	}
+}
+
+func TestOpenSessionRejectsBadIndex(t *testing.T) {
+	setupTestStore(t)
+	configDir, _ := ConfigDir()
+	dir := filepath.Join(configDir, "sessions")
+	os.MkdirAll(dir, 0755)
+
+	for _, stack := range []string{
+		`{"entries": ["/a", "/b"], "index": 2}`,
+		`{"entries": ["/a"], "index": -1}`,
+		`{"entries": [], "index": 1}`,
+	} {
+		os.WriteFile(filepath.Join(dir, "bad.json"), []byte(stack), 0644)
+		if _, err := OpenSession("bad"); !errors.Is(err, ErrCorruptStore) {
+			t.Errorf("Expected ErrCorruptStore for %s, got %v", stack, err)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "empty.json"), []byte(`{"entries": null, "index": 0}`), 0644)
+	if _, err := OpenSession("empty"); err != nil {
+		t.Errorf("Expected an empty stack to load, got %v", err)
+	}
+}
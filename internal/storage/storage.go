@@ -13,30 +13,49 @@ import (
 )
 
 type Bookmark struct {
-	Path      string    `json:"path"`
-	Created   time.Time `json:"created"`
-	UsedCount int       `json:"used_count"`
-	LastUsed  time.Time `json:"last_used"`
+	Path      string            `json:"path"`
+	Created   time.Time         `json:"created"`
+	UsedCount int               `json:"used_count"`
+	LastUsed  time.Time         `json:"last_used"`
+	Tags      []string          `json:"tags,omitempty"`
+	File      bool              `json:"file,omitempty"`   // Path is a file rather than a directory
+	Opener    string            `json:"opener,omitempty"` // 'fn open' opener for this bookmark, if not the default
+	Hooks     []string          `json:"hooks,omitempty"`  // shell snippets run after entering the bookmark
+	Env       map[string]string `json:"env,omitempty"`    // variables exported while inside the bookmark
+	Tasks     map[string]string `json:"tasks,omitempty"`  // named shell commands for 'fn run'
+}
+
+// Dir returns the directory to navigate to: the bookmark's path, or the
+// directory containing it for file bookmarks
+func (b *Bookmark) Dir() string {
+	if b.File {
+		return filepath.Dir(b.Path)
+	}
+	return b.Path
 }
 
 type BookmarkData struct {
 	Version   string               `json:"version"`
 	Bookmarks map[string]*Bookmark `json:"bookmarks"`
+	// Synonyms maps each secondary name to the alias it stands for
+	Synonyms map[string]string `json:"synonyms,omitempty"`
 }
 
 type Store struct {
 	configDir string
 	filePath  string
 	data      *BookmarkData
+	batching  int // depth of nested Batch calls
+	// renames are the usage log rewrites waiting for a batch to be written
+	renames [][2]string
 }
 
 func NewStore() (*Store, error) {
-	homeDir, err := homedir.Dir()
+	configDir, err := ConfigDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 	
-	configDir := filepath.Join(homeDir, ".fn")
 	filePath := filepath.Join(configDir, "bookmarks.json")
 	
 	store := &Store{
@@ -57,9 +76,22 @@ func NewStore() (*Store, error) {
 	return store, nil
 }
 
+// ConfigDir returns the directory holding all fn data files (~/.fn)
+func ConfigDir() (string, error) {
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".fn"), nil
+}
+
 func (s *Store) ensureConfigDir() error {
-	if _, err := os.Stat(s.configDir); os.IsNotExist(err) {
-		return os.MkdirAll(s.configDir, 0755)
+	return ensureDir(s.configDir)
+}
+
+func ensureDir(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return os.MkdirAll(dir, 0755)
 	}
 	return nil
 }
@@ -82,7 +114,7 @@ func (s *Store) load() error {
 	s.data = &BookmarkData{}
 	err = json.Unmarshal(file, s.data)
 	if err != nil {
-		return fmt.Errorf("failed to parse bookmarks file: %w", err)
+		return fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, s.filePath, err)
 	}
 	
 	// Initialize bookmarks map if nil
@@ -93,7 +125,30 @@ func (s *Store) load() error {
 	return nil
 }
 
+// ReadBookmarksFile reads the bookmarks and synonyms from a copy of
+// bookmarks.json, or from a JSON file written by 'fn export'
+func ReadBookmarksFile(path string) (*BookmarkData, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	data := &BookmarkData{}
+	err = json.Unmarshal(file, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, path, err)
+	}
+	if data.Bookmarks == nil {
+		data.Bookmarks = make(map[string]*Bookmark)
+	}
+	return data, nil
+}
+
 func (s *Store) save() error {
+	if s.batching > 0 {
+		return nil
+	}
+
 	data, err := json.MarshalIndent(s.data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal bookmarks: %w", err)
@@ -108,11 +163,24 @@ func (s *Store) save() error {
 }
 
 func (s *Store) SaveBookmark(alias, path string) error {
+	return s.saveBookmark(alias, path, false)
+}
+
+// SaveFileBookmark saves a bookmark to a single file
+func (s *Store) SaveFileBookmark(alias, path string) error {
+	return s.saveBookmark(alias, path, true)
+}
+
+func (s *Store) saveBookmark(alias, path string, file bool) error {
+	if err := s.checkNotSynonym(alias); err != nil {
+		return err
+	}
 	now := time.Now()
 	
 	if existing, exists := s.data.Bookmarks[alias]; exists {
 		// Update existing bookmark
 		existing.Path = path
+		existing.File = file
 		existing.LastUsed = now
 	} else {
 		// Create new bookmark
@@ -121,12 +189,23 @@ func (s *Store) SaveBookmark(alias, path string) error {
 			Created:   now,
 			UsedCount: 0,
 			LastUsed:  now,
+			File:      file,
 		}
 	}
 	
 	return s.save()
 }
 
+// PutBookmark stores a complete bookmark under alias, replacing any existing
+// one. Unlike SaveBookmark it keeps the given timestamps and usage count.
+func (s *Store) PutBookmark(alias string, bookmark *Bookmark) error {
+	if err := s.checkNotSynonym(alias); err != nil {
+		return err
+	}
+	s.data.Bookmarks[alias] = bookmark
+	return s.save()
+}
+
 func (s *Store) GetBookmark(alias string) (*Bookmark, bool) {
 	bookmark, exists := s.data.Bookmarks[alias]
 	return bookmark, exists
@@ -136,18 +215,191 @@ func (s *Store) GetAllBookmarks() map[string]*Bookmark {
 	return s.data.Bookmarks
 }
 
+// FindByPath returns the aliases of all bookmarks whose directory is path,
+// sorted. File bookmarks count for the directory containing the file.
+func (s *Store) FindByPath(path string) []string {
+	path = filepath.Clean(path)
+	var aliases []string
+	for alias, bookmark := range s.data.Bookmarks {
+		if filepath.Clean(bookmark.Dir()) == path {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
 func (s *Store) DeleteBookmark(alias string) error {
 	delete(s.data.Bookmarks, alias)
+	s.deleteSynonyms(alias)
+	return s.save()
+}
+
+// RenameBookmark moves a bookmark to a new alias, keeping its history and
+// stats, including its entries in the usage log
+func (s *Store) RenameBookmark(oldAlias, newAlias string) error {
+	bookmark, exists := s.data.Bookmarks[oldAlias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, oldAlias)
+	}
+	if _, taken := s.data.Bookmarks[newAlias]; taken {
+		return fmt.Errorf("bookmark already exists: %s", newAlias)
+	}
+	if err := s.checkNotSynonym(newAlias); err != nil {
+		return err
+	}
+
+	delete(s.data.Bookmarks, oldAlias)
+	s.data.Bookmarks[newAlias] = bookmark
+	s.renameSynonyms(oldAlias, newAlias)
+	if s.batching > 0 {
+		// The log must not name the new alias before bookmarks.json does
+		s.renames = append(s.renames, [2]string{oldAlias, newAlias})
+		return nil
+	}
+	err := s.save()
+	if err != nil {
+		return err
+	}
+	return s.renameUsage(oldAlias, newAlias)
+}
+
+// SetPath re-points a bookmark, keeping its kind, usage stats and settings
+func (s *Store) SetPath(alias, path string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	bookmark.Path = path
+	return s.save()
+}
+
+// SetTags replaces the tags of a bookmark
+func (s *Store) SetTags(alias string, tags []string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	bookmark.Tags = normalizeTags(tags)
+	return s.save()
+}
+
+// SetHooks replaces the on-enter hooks of a bookmark
+func (s *Store) SetHooks(alias string, hooks []string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	bookmark.Hooks = hooks
+	return s.save()
+}
+
+// SetEnv replaces the environment variables of a bookmark
+func (s *Store) SetEnv(alias string, env map[string]string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	if len(env) == 0 {
+		env = nil
+	}
+	bookmark.Env = env
+	return s.save()
+}
+
+// SetTasks replaces the named commands of a bookmark
+func (s *Store) SetTasks(alias string, tasks map[string]string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	if len(tasks) == 0 {
+		tasks = nil
+	}
+	bookmark.Tasks = tasks
+	return s.save()
+}
+
+// SetOpener sets the opener 'fn open' uses for a bookmark; an empty name
+// goes back to the default
+func (s *Store) SetOpener(alias, opener string) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
+	}
+
+	bookmark.Opener = opener
 	return s.save()
 }
 
+// Batch runs fn with saving deferred and writes the store once when fn
+// succeeds. If fn fails, its changes are kept in memory but not written.
+// Renames reach the usage log only once the store has been written.
+// Batches nest: only the outermost one writes.
+func (s *Store) Batch(fn func() error) error {
+	s.batching++
+	err := fn()
+	s.batching--
+	if s.batching > 0 {
+		return err
+	}
+	renames := s.renames
+	s.renames = nil
+	if err != nil {
+		return err
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+	for _, rename := range renames {
+		if err := s.renameUsage(rename[0], rename[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateUsage counts an exact-alias use of a bookmark
 func (s *Store) UpdateUsage(alias string) error {
-	if bookmark, exists := s.data.Bookmarks[alias]; exists {
-		bookmark.UsedCount++
-		bookmark.LastUsed = time.Now()
-		return s.save()
+	return s.UpdateUsageWith(alias, ResolutionExact)
+}
+
+// UpdateUsageWith counts a use of a bookmark and appends it to the usage log
+func (s *Store) UpdateUsageWith(alias string, resolution Resolution) error {
+	bookmark, exists := s.data.Bookmarks[alias]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, alias)
 	}
-	return fmt.Errorf("bookmark not found: %s", alias)
+
+	bookmark.UsedCount++
+	bookmark.LastUsed = time.Now()
+
+	err := s.save()
+	if err != nil {
+		return err
+	}
+	return s.appendUsage(UsageEvent{Time: bookmark.LastUsed, Alias: alias, Resolution: resolution})
+}
+
+// normalizeTags trims, de-duplicates and sorts tags, dropping empty ones
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // FuzzyMatch represents a fuzzy match result
@@ -165,6 +417,10 @@ func (s *Store) FindFuzzyMatches(pattern string) []FuzzyMatch {
 	for alias, bookmark := range s.data.Bookmarks {
 		aliasLower := strings.ToLower(alias)
 		score := calculateFuzzyScore(pattern, aliasLower)
+		// A synonym matches on behalf of its bookmark, which is listed once
+		for _, synonym := range s.SynonymsOf(alias) {
+			score = max(score, calculateFuzzyScore(pattern, strings.ToLower(synonym)))
+		}
 		
 		if score > 0 {
 			matches = append(matches, FuzzyMatch{
@@ -186,6 +442,24 @@ func (s *Store) FindFuzzyMatches(pattern string) []FuzzyMatch {
 	return matches
 }
 
+// Resolve finds the bookmark for an alias, falling back to fuzzy matching.
+// It returns ErrNotFound if nothing matches and an *AmbiguousError if the
+// pattern matches several bookmarks.
+func (s *Store) Resolve(pattern string) (string, *Bookmark, error) {
+	if alias, bookmark, exists := s.Lookup(pattern); exists {
+		return alias, bookmark, nil
+	}
+
+	matches := s.FindFuzzyMatches(pattern)
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("%w matching '%s'", ErrNotFound, pattern)
+	case 1:
+		return matches[0].Alias, matches[0].Bookmark, nil
+	}
+	return "", nil, &AmbiguousError{Pattern: pattern, Matches: matches}
+}
+
 // calculateFuzzyScore calculates a score for how well the pattern matches the alias
 func calculateFuzzyScore(pattern, alias string) int {
 	if pattern == alias {
@@ -1,11 +1,20 @@
 package storage
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
 )
 
+func init() {
+	// Tests point HOME at temporary directories, so home lookups must not be cached
+	homedir.DisableCache = true
+}
+
 func TestNewStore(t *testing.T) {
 	// Create temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "fn-test-*")
@@ -63,6 +72,29 @@ func TestSaveBookmark(t *testing.T) {
 	}
 }
 
+func TestSaveFileBookmark(t *testing.T) {
+	store := setupTestStore(t)
+
+	err := store.SaveFileBookmark("runbook", "/ops/runbook.md")
+	if err != nil {
+		t.Fatalf("SaveFileBookmark() failed: %v", err)
+	}
+
+	bookmark, _ := store.GetBookmark("runbook")
+	if !bookmark.File || bookmark.Dir() != "/ops" {
+		t.Errorf("Expected a file bookmark in /ops, got %+v (dir %s)", bookmark, bookmark.Dir())
+	}
+	if aliases := store.FindByPath("/ops"); len(aliases) != 1 {
+		t.Errorf("Expected FindByPath to match the file's directory, got %v", aliases)
+	}
+
+	// Re-saving as a directory clears the flag
+	store.SaveBookmark("runbook", "/ops")
+	if bookmark.File || bookmark.Dir() != "/ops" {
+		t.Errorf("Expected a directory bookmark, got %+v", bookmark)
+	}
+}
+
 func TestUpdateExistingBookmark(t *testing.T) {
 	store := setupTestStore(t)
 	
@@ -200,6 +232,174 @@ func TestGetAllBookmarks(t *testing.T) {
 	}
 }
 
+func TestRenameBookmark(t *testing.T) {
+	store := setupTestStore(t)
+
+	err := store.SaveBookmark("old", "/tmp/old")
+	if err != nil {
+		t.Fatalf("SaveBookmark() failed: %v", err)
+	}
+	err = store.UpdateUsage("old")
+	if err != nil {
+		t.Fatalf("UpdateUsage() failed: %v", err)
+	}
+
+	err = store.RenameBookmark("old", "new")
+	if err != nil {
+		t.Fatalf("RenameBookmark() failed: %v", err)
+	}
+
+	if _, exists := store.GetBookmark("old"); exists {
+		t.Error("Expected old alias to be gone")
+	}
+
+	bookmark, exists := store.GetBookmark("new")
+	if !exists {
+		t.Fatal("Expected new alias to exist")
+	}
+	if bookmark.UsedCount != 1 {
+		t.Errorf("Expected UsedCount 1 to be preserved, got %d", bookmark.UsedCount)
+	}
+
+	events, err := store.UsageEvents(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("UsageEvents() failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Alias != "new" {
+		t.Errorf("Expected the usage log to follow the rename, got %+v", events)
+	}
+
+	// Renaming onto an existing alias must fail
+	err = store.SaveBookmark("other", "/tmp/other")
+	if err != nil {
+		t.Fatalf("SaveBookmark() failed: %v", err)
+	}
+	if err := store.RenameBookmark("new", "other"); err == nil {
+		t.Error("Expected error when renaming onto an existing alias")
+	}
+}
+
+func TestSetTags(t *testing.T) {
+	store := setupTestStore(t)
+
+	err := store.SaveBookmark("test", "/tmp/test")
+	if err != nil {
+		t.Fatalf("SaveBookmark() failed: %v", err)
+	}
+
+	err = store.SetTags("test", []string{" work ", "go", "", "work"})
+	if err != nil {
+		t.Fatalf("SetTags() failed: %v", err)
+	}
+
+	bookmark, _ := store.GetBookmark("test")
+	if len(bookmark.Tags) != 2 || bookmark.Tags[0] != "go" || bookmark.Tags[1] != "work" {
+		t.Errorf("Expected tags [go work], got %v", bookmark.Tags)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	store := setupTestStore(t)
+
+	err := store.Batch(func() error {
+		if err := store.SaveBookmark("one", "/tmp/one"); err != nil {
+			return err
+		}
+
+		// Nothing should reach the disk until the batch finishes
+		reloaded, err := NewStore()
+		if err != nil {
+			return err
+		}
+		if _, exists := reloaded.GetBookmark("one"); exists {
+			t.Error("Expected bookmark not to be written during batch")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch() failed: %v", err)
+	}
+
+	reloaded, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore() failed: %v", err)
+	}
+	if _, exists := reloaded.GetBookmark("one"); !exists {
+		t.Error("Expected bookmark to be written after batch")
+	}
+}
+
+func TestNestedBatch(t *testing.T) {
+	store := setupTestStore(t)
+
+	failed := errors.New("failed")
+	err := store.Batch(func() error {
+		store.Batch(func() error {
+			return store.SaveBookmark("inner", "/tmp/inner")
+		})
+		if err := store.SaveBookmark("outer", "/tmp/outer"); err != nil {
+			return err
+		}
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("Expected the batch error, got %v", err)
+	}
+
+	reloaded, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore() failed: %v", err)
+	}
+	if len(reloaded.GetAllBookmarks()) != 0 {
+		t.Errorf("Expected an inner batch not to write on its own, got %v", reloaded.GetAllBookmarks())
+	}
+}
+
+func TestBatchRenameUsage(t *testing.T) {
+	store := setupTestStore(t)
+	store.SaveBookmark("old", "/tmp/old")
+	store.UpdateUsage("old")
+
+	logged := func() string {
+		events, err := store.UsageEvents(time.Time{}, time.Time{})
+		if err != nil || len(events) != 1 {
+			t.Fatalf("Expected one usage event, got %+v, %v", events, err)
+		}
+		return events[0].Alias
+	}
+
+	// A failed batch leaves the log alone
+	failed := errors.New("failed")
+	err := store.Batch(func() error {
+		if err := store.RenameBookmark("old", "new"); err != nil {
+			return err
+		}
+		if alias := logged(); alias != "old" {
+			t.Errorf("Expected the log not to change during the batch, got %q", alias)
+		}
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("Expected the batch error, got %v", err)
+	}
+	if alias := logged(); alias != "old" {
+		t.Errorf("Expected the log to be untouched by a failed batch, got %q", alias)
+	}
+
+	store = setupTestStore(t)
+	store.SaveBookmark("old", "/tmp/old")
+	store.UpdateUsage("old")
+	err = store.Batch(func() error {
+		return store.RenameBookmark("old", "new")
+	})
+	if err != nil {
+		t.Fatalf("Batch() failed: %v", err)
+	}
+	if alias := logged(); alias != "new" {
+		t.Errorf("Expected the log to follow the rename after the batch, got %q", alias)
+	}
+}
+
 // setupTestStore creates a temporary store for testing
 func setupTestStore(t *testing.T) *Store {
 	tempDir, err := os.MkdirTemp("", "fn-test-*")
@@ -225,4 +425,50 @@ func setupTestStore(t *testing.T) *Store {
 	}
 
 	return store
-}
\ No newline at end of file
+}
+
+func TestResolveErrors(t *testing.T) {
+	store := setupTestStore(t)
+	store.SaveBookmark("project-a", "/tmp/a")
+	store.SaveBookmark("project-b", "/tmp/b")
+	store.SaveBookmark("docs", "/tmp/docs")
+
+	alias, bookmark, err := store.Resolve("docs")
+	if err != nil || alias != "docs" || bookmark.Path != "/tmp/docs" {
+		t.Errorf("Expected exact match for docs, got %q %v %v", alias, bookmark, err)
+	}
+
+	_, _, err = store.Resolve("project")
+	var ambiguous *AmbiguousError
+	if !errors.Is(err, ErrAmbiguous) || !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 {
+		t.Errorf("Expected ambiguous match with 2 candidates, got %v", err)
+	}
+
+	_, _, err = store.Resolve("zzzzzz")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.UpdateUsage("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from UpdateUsage, got %v", err)
+	}
+
+	if err := CheckDir(filepath.Join(t.TempDir(), "gone")); !errors.Is(err, ErrPathMissing) {
+		t.Errorf("Expected ErrPathMissing, got %v", err)
+	}
+}
+
+func TestCorruptStore(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	os.MkdirAll(filepath.Join(tempDir, ".fn"), 0755)
+	os.WriteFile(filepath.Join(tempDir, ".fn", "bookmarks.json"), []byte("{not json"), 0644)
+
+	_, err := NewStore()
+	if !errors.Is(err, ErrCorruptStore) {
+		t.Errorf("Expected ErrCorruptStore, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AddSynonym gives the bookmark another name. Synonyms resolve like the
+// alias itself and count uses towards it, but the bookmark is still listed
+// once, under its alias. The bookmark may be named by a synonym too.
+func (s *Store) AddSynonym(name, synonym string) error {
+	alias, _, exists := s.Lookup(name)
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if _, taken := s.data.Bookmarks[synonym]; taken {
+		return fmt.Errorf("'%s' is already a bookmark", synonym)
+	}
+	if other, taken := s.data.Synonyms[synonym]; taken {
+		if other == alias {
+			return nil
+		}
+		return fmt.Errorf("'%s' is already a synonym of '%s'", synonym, other)
+	}
+
+	if s.data.Synonyms == nil {
+		s.data.Synonyms = make(map[string]string)
+	}
+	s.data.Synonyms[synonym] = alias
+	return s.save()
+}
+
+// SetSynonyms replaces all synonyms. Each must stand for an existing
+// bookmark and must not be an alias itself.
+func (s *Store) SetSynonyms(synonyms map[string]string) error {
+	for synonym, alias := range synonyms {
+		if _, exists := s.data.Bookmarks[alias]; !exists {
+			return fmt.Errorf("%w: %s (synonym '%s')", ErrNotFound, alias, synonym)
+		}
+		if _, taken := s.data.Bookmarks[synonym]; taken {
+			return fmt.Errorf("'%s' is already a bookmark", synonym)
+		}
+	}
+
+	s.data.Synonyms = nil
+	if len(synonyms) > 0 {
+		s.data.Synonyms = make(map[string]string, len(synonyms))
+		for synonym, alias := range synonyms {
+			s.data.Synonyms[synonym] = alias
+		}
+	}
+	return s.save()
+}
+
+// RemoveSynonym deletes a synonym, leaving its bookmark alone
+func (s *Store) RemoveSynonym(synonym string) error {
+	if _, exists := s.data.Synonyms[synonym]; !exists {
+		return fmt.Errorf("'%s' is not a synonym", synonym)
+	}
+	delete(s.data.Synonyms, synonym)
+	return s.save()
+}
+
+// SynonymsOf returns the synonyms of a bookmark, sorted
+func (s *Store) SynonymsOf(alias string) []string {
+	var synonyms []string
+	for synonym, target := range s.data.Synonyms {
+		if target == alias {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	sort.Strings(synonyms)
+	return synonyms
+}
+
+// GetSynonyms returns all synonyms, mapped to the alias they stand for
+func (s *Store) GetSynonyms() map[string]string {
+	return s.data.Synonyms
+}
+
+// Lookup finds a bookmark by its alias or one of its synonyms, without
+// fuzzy matching, and returns its alias
+func (s *Store) Lookup(name string) (string, *Bookmark, bool) {
+	if bookmark, exists := s.data.Bookmarks[name]; exists {
+		return name, bookmark, true
+	}
+	if alias, exists := s.data.Synonyms[name]; exists {
+		if bookmark, exists := s.data.Bookmarks[alias]; exists {
+			return alias, bookmark, true
+		}
+	}
+	return "", nil, false
+}
+
+// checkNotSynonym returns ErrSynonym if alias is in use as a synonym, so
+// that a bookmark never silently shadows one
+func (s *Store) checkNotSynonym(alias string) error {
+	if target, taken := s.data.Synonyms[alias]; taken {
+		return fmt.Errorf("%w: '%s' stands for '%s'", ErrSynonym, alias, target)
+	}
+	return nil
+}
+
+// renameSynonyms points the synonyms of oldAlias at newAlias
+func (s *Store) renameSynonyms(oldAlias, newAlias string) {
+	for synonym, target := range s.data.Synonyms {
+		if target == oldAlias {
+			s.data.Synonyms[synonym] = newAlias
+		}
+	}
+}
+
+// deleteSynonyms drops the synonyms of alias
+func (s *Store) deleteSynonyms(alias string) {
+	for synonym, target := range s.data.Synonyms {
+		if target == alias {
+			delete(s.data.Synonyms, synonym)
+		}
+	}
+}
+
+// synonymContains reports whether any synonym of alias contains the
+// lower-case pattern
+func (s *Store) synonymContains(alias, pattern string) bool {
+	for _, synonym := range s.SynonymsOf(alias) {
+		if strings.Contains(strings.ToLower(synonym), pattern) {
+			return true
+		}
+	}
+	return false
+}
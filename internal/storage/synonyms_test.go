@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSynonyms(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	store.SaveBookmark("k8s", "/srv/k8s")
+	store.SaveBookmark("web", "/srv/web")
+
+	if err := store.AddSynonym("k8s", "kube"); err != nil {
+		t.Fatalf("AddSynonym failed: %v", err)
+	}
+	if err := store.AddSynonym("kube", "cluster"); err != nil {
+		t.Fatalf("AddSynonym by synonym failed: %v", err)
+	}
+	if err := store.AddSynonym("web", "k8s"); err == nil {
+		t.Error("Expected an error for a synonym that is an alias")
+	}
+	if err := store.AddSynonym("web", "kube"); err == nil {
+		t.Error("Expected an error for a synonym of another bookmark")
+	}
+	if err := store.AddSynonym("nope", "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := store.PutBookmark("kube", &Bookmark{Path: "/srv/kube"}); !errors.Is(err, ErrSynonym) {
+		t.Errorf("Expected PutBookmark to refuse a synonym, got %v", err)
+	}
+	if err := store.RenameBookmark("web", "kube"); !errors.Is(err, ErrSynonym) {
+		t.Errorf("Expected RenameBookmark to refuse a synonym, got %v", err)
+	}
+
+	store, _ = NewStore()
+	if got := store.SynonymsOf("k8s"); !reflect.DeepEqual(got, []string{"cluster", "kube"}) {
+		t.Errorf("SynonymsOf = %v after reloading", got)
+	}
+
+	alias, _, err := store.Resolve("cluster")
+	if err != nil || alias != "k8s" {
+		t.Errorf("Resolve(cluster) = %q, %v; want k8s", alias, err)
+	}
+	matches := store.FindFuzzyMatches("ku")
+	if len(matches) != 1 || matches[0].Alias != "k8s" {
+		t.Errorf("Expected one fuzzy match under the alias, got %v", matches)
+	}
+	found, err := store.Query(Query{Pattern: "clus"})
+	if err != nil || len(found) != 1 || found[0].Alias != "k8s" {
+		t.Errorf("Expected the query to match by synonym, got %v, %v", found, err)
+	}
+
+	if err := store.RenameBookmark("k8s", "infra"); err != nil {
+		t.Fatalf("RenameBookmark failed: %v", err)
+	}
+	if alias, _, _ := store.Lookup("kube"); alias != "infra" {
+		t.Errorf("Expected synonyms to follow the rename, got %q", alias)
+	}
+
+	if err := store.RemoveSynonym("cluster"); err != nil {
+		t.Fatalf("RemoveSynonym failed: %v", err)
+	}
+	if err := store.RemoveSynonym("cluster"); err == nil {
+		t.Error("Expected an error removing a missing synonym")
+	}
+
+	if err := store.SetSynonyms(map[string]string{"web": "infra"}); err == nil {
+		t.Error("Expected SetSynonyms to refuse a synonym that is an alias")
+	}
+	if err := store.SetSynonyms(map[string]string{"site": "gone"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected SetSynonyms to refuse a missing bookmark, got %v", err)
+	}
+
+	store.DeleteBookmark("infra")
+	if len(store.GetSynonyms()) != 0 {
+		t.Errorf("Expected synonyms to go with their bookmark, got %v", store.GetSynonyms())
+	}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TrustData is the on-disk allow-list of hooks, variables and tasks
+type TrustData struct {
+	Version string `json:"version"`
+	// Allowed maps an alias to the digest of its allowed hooks, variables and
+	// tasks
+	Allowed map[string]string `json:"allowed"`
+}
+
+// TrustList records which bookmark hooks, environment variables and tasks
+// the user has reviewed and allowed. It lives outside bookmarks.json, so
+// anything arriving through import, merge or sync never takes effect until
+// it is allowed on this machine, and changing a bookmark's path, hooks,
+// variables or tasks withdraws the permission.
+type TrustList struct {
+	filePath string
+	data     *TrustData
+}
+
+// LoadTrust reads ~/.fn/trusted.json, which may not exist yet
+func LoadTrust() (*TrustList, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	trust := &TrustList{
+		filePath: filepath.Join(configDir, "trusted.json"),
+		data:     &TrustData{Version: "1.0", Allowed: make(map[string]string)},
+	}
+
+	file, err := os.ReadFile(trust.filePath)
+	if os.IsNotExist(err) {
+		return trust, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust file: %w", err)
+	}
+
+	err = json.Unmarshal(file, trust.data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %v", ErrCorruptStore, trust.filePath, err)
+	}
+	if trust.data.Allowed == nil {
+		trust.data.Allowed = make(map[string]string)
+	}
+	return trust, nil
+}
+
+// HookDigest fingerprints a bookmark's path together with its hooks,
+// environment variables and tasks. The encoding keeps every field apart, so
+// a hook can never hash like a variable or a task.
+func HookDigest(bookmark *Bookmark) string {
+	// Marshalling strings, slices and maps of strings cannot fail. Maps are
+	// written in key order, and empty fields are left out whether nil or not.
+	encoded, _ := json.Marshal(struct {
+		Path  string            `json:"path"`
+		Hooks []string          `json:"hooks,omitempty"`
+		Env   map[string]string `json:"env,omitempty"`
+		Tasks map[string]string `json:"tasks,omitempty"`
+	}{bookmark.Path, bookmark.Hooks, bookmark.Env, bookmark.Tasks})
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// Trusted reports whether the bookmark's current hooks, variables and tasks
+// were allowed
+func (t *TrustList) Trusted(alias string, bookmark *Bookmark) bool {
+	return t.data.Allowed[alias] == HookDigest(bookmark)
+}
+
+// Allow trusts the bookmark's current hooks, variables and tasks
+func (t *TrustList) Allow(alias string, bookmark *Bookmark) error {
+	t.data.Allowed[alias] = HookDigest(bookmark)
+	return t.save()
+}
+
+// Deny withdraws trust from any hooks, variables and tasks of the alias
+func (t *TrustList) Deny(alias string) error {
+	delete(t.data.Allowed, alias)
+	return t.save()
+}
+
+// Rename moves the permission of oldAlias to newAlias, dropping whatever
+// newAlias had
+func (t *TrustList) Rename(oldAlias, newAlias string) error {
+	digest, allowed := t.data.Allowed[oldAlias]
+	delete(t.data.Allowed, oldAlias)
+	delete(t.data.Allowed, newAlias)
+	if allowed {
+		t.data.Allowed[newAlias] = digest
+	}
+	return t.save()
+}
+
+func (t *TrustList) save() error {
+	err := ensureDir(filepath.Dir(t.filePath))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(t.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trust list: %w", err)
+	}
+
+	err = os.WriteFile(t.filePath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write trust file: %w", err)
+	}
+	return nil
+}
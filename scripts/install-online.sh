@@ -119,14 +119,7 @@ case "$SHELL_NAME" in
     fish)
         echo -e "${YELLOW}Fish shell detected. Manual setup required.${NC}"
         echo -e "${YELLOW}Add this to your Fish config:${NC}"
-        echo -e "${BLUE}function fn --description 'Fast navigation with bookmarks'${NC}"
-        echo -e "${BLUE}    set result (command fn navigate \$argv 2>/dev/null)${NC}"
-        echo -e "${BLUE}    if test \$status -eq 0${NC}"
-        echo -e "${BLUE}        cd \$result${NC}"
-        echo -e "${BLUE}    else${NC}"
-        echo -e "${BLUE}        command fn \$argv${NC}"
-        echo -e "${BLUE}    end${NC}"
-        echo -e "${BLUE}end${NC}"
+        echo -e "${BLUE}fn init fish | source${NC}"
         ;;
     *)
         echo -e "${YELLOW}Unknown shell: $SHELL_NAME${NC}"
@@ -139,34 +132,13 @@ if [[ -n "$SHELL_RC" ]]; then
     echo -e "${BLUE}Installing shell function to $SHELL_RC...${NC}"
     
     # Check if function already exists
-    if grep -q "fn() {" "$SHELL_RC" 2>/dev/null; then
+    if grep -q "fn init" "$SHELL_RC" 2>/dev/null; then
         echo -e "${YELLOW}Shell function already exists in $SHELL_RC${NC}"
     else
-        cat >> "$SHELL_RC" << 'EOF'
+        cat >> "$SHELL_RC" << EOF
 
 # fn - Fast Navigation Tool
-fn() {
-    if [[ $# -eq 0 ]]; then
-        command fn --help
-        return
-    fi
-    
-    case "$1" in
-        save|list|delete|edit|cleanup|search|recent|r|path|help|completion)
-            command fn "$@"
-            ;;
-        *)
-            # Try to navigate
-            local result
-            result=$(command fn navigate "$1" 2>/dev/null)
-            if [[ $? -eq 0 && -n "$result" ]]; then
-                cd "$result"
-            else
-                command fn "$@"
-            fi
-            ;;
-    esac
-}
+eval "\$(command fn init $SHELL_NAME)"
 EOF
         echo -e "${GREEN}Shell function added to $SHELL_RC${NC}"
     fi
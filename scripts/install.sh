@@ -141,11 +141,11 @@ detect_shell() {
             ;;
         zsh)
             shell_config="$HOME/.zshrc"
-            shell_function_type="bash"  # zsh is bash-compatible for our purposes
+            shell_function_type="zsh"
             ;;
         fish)
-            mkdir -p "$HOME/.config/fish/functions" 2>/dev/null
-            shell_config="$HOME/.config/fish/functions/fn.fish"
+            mkdir -p "$HOME/.config/fish/conf.d" 2>/dev/null
+            shell_config="$HOME/.config/fish/conf.d/fn.fish"
             shell_function_type="fish"
             ;;
         sh|dash|ash)
@@ -176,58 +176,37 @@ detect_shell() {
     echo "$shell_config|$shell_function_type"
 }
 
-# Generate shell function based on shell type
+# Generate shell integration based on shell type. The function itself is
+# produced by 'fast-nav init' at shell startup so it always matches the binary.
 generate_shell_function() {
     local function_type="$1"
     
     case $function_type in
-        bash|posix)
-            cat << 'EOF'
+        bash|zsh)
+            cat << EOF
 
 # fn - Fast Navigation
-fn() {
-    case "$1" in
-        save|list|delete|path|edit|cleanup)
-            command fast-nav "$@"
-            ;;
-        *)
-            local dir
-            dir=$(command fast-nav navigate "$@" 2>/dev/null)
-            if [ -n "$dir" ] && [ -d "$dir" ]; then
-                cd "$dir" || return 1
-            fi
+eval "\$(command fast-nav init $function_type --cmd fn)"
+EOF
             ;;
-    esac
-}
+        posix)
+            cat << 'EOF'
+
+# fn - Fast Navigation
+eval "$(command fast-nav init bash --cmd fn --no-record)"
 EOF
             ;;
         fish)
-            # For fish, we create a complete function file
             cat << 'EOF'
 # fn - Fast Navigation
-function fn
-    switch $argv[1]
-        case save list delete path edit cleanup
-            command fast-nav $argv
-        case '*'
-            set -l dir (command fast-nav navigate $argv 2>/dev/null)
-            if test -n "$dir" -a -d "$dir"
-                cd "$dir"
-            end
-    end
-end
+fast-nav init fish --cmd fn | source
 EOF
             ;;
         csh)
             cat << 'EOF'
 
 # fn - Fast Navigation
-alias fn 'if ("\!:1" == "save" || "\!:1" == "list" || "\!:1" == "delete" || "\!:1" == "path" || "\!:1" == "edit" || "\!:1" == "cleanup") then \\
-    command fast-nav \!* \\
-else \\
-    set fn_dir = `command fast-nav navigate \!* |& grep -v "^$"` \\
-    if ( "$fn_dir" != "" && -d "$fn_dir" ) cd "$fn_dir" \\
-endif'
+fast-nav init tcsh --cmd fn >! ~/.fn.tcsh && source ~/.fn.tcsh
 EOF
             ;;
     esac
@@ -242,18 +221,7 @@ function_exists() {
         return 1
     fi
     
-    case $function_type in
-        fish)
-            # Fish function files are self-contained
-            return 0
-            ;;
-        csh)
-            grep -q "alias fn" "$shell_config" 2>/dev/null
-            ;;
-        *)
-            grep -E "^[[:space:]]*(function[[:space:]]+)?fn[[:space:]]*\(\)" "$shell_config" 2>/dev/null
-            ;;
-    esac
+    grep -q "fast-nav init" "$shell_config" 2>/dev/null
 }
 
 # Add shell function
@@ -284,7 +252,7 @@ add_shell_function() {
     
     print_status "Adding shell function to $shell_config..."
     
-    # For fish, we write directly to the function file
+    # For fish, we write directly to the conf.d file
     if [[ "$function_type" == "fish" ]]; then
         generate_shell_function "$function_type" > "$shell_config"
     else